@@ -0,0 +1,88 @@
+package ddbexportpoller
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// ErrAccountRegionRegionRequired is an error that means the region of AccountRegion is not passed
+var ErrAccountRegionRegionRequired = errors.New("region of the account region required")
+
+// AccountRegion is a pair of an AWS account and a region where tables and exports the Poller accesses reside.
+//
+// The Poller builds one DynamoDB client for each AccountRegion and routes the requests on the table or the export to the client by the account ID and the region in its ARN.
+type AccountRegion struct {
+	// RoleArn is the ARN of the IAM role assumed to access the account.
+	//
+	// Empty means the base credentials are used and the ARNs in any account in the region are routed to the client.
+	RoleArn string
+
+	// Region is the region of the tables and the exports
+	Region string
+}
+
+func (a AccountRegion) validate() error {
+	if a.Region == "" {
+		return ErrAccountRegionRegionRequired
+	}
+	if a.RoleArn == "" {
+		return nil
+	}
+	parsed, err := arn.Parse(a.RoleArn)
+	if err != nil {
+		return &InvalidARNError{ARN: a.RoleArn, Resource: "role", Reason: err.Error()}
+	}
+	if parsed.Service != "iam" || !strings.HasPrefix(parsed.Resource, "role/") || parsed.AccountID == "" {
+		return &InvalidARNError{ARN: a.RoleArn, Resource: "role", Reason: "must be an IAM role ARN"}
+	}
+	return nil
+}
+
+func (a AccountRegion) key() clientKey {
+	key := clientKey{region: a.Region}
+	if parsed, err := arn.Parse(a.RoleArn); err == nil {
+		key.accountID = parsed.AccountID
+	}
+	return key
+}
+
+// newClient builds the DynamoDB client that accesses the region with the credentials of the assumed role.
+func (a AccountRegion) newClient(base aws.Config) Client {
+	cfg := base.Copy()
+	cfg.Region = a.Region
+	if a.RoleArn != "" {
+		cfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(sts.NewFromConfig(base), a.RoleArn))
+	}
+	return dynamodb.NewFromConfig(cfg)
+}
+
+type clientKey struct {
+	accountID string
+	region    string
+}
+
+// clientFor returns the client routed by the account ID and the region in the ARN of the table or the export.
+//
+// The client for the region regardless of the account is used if no clients for the account are configured, and the default client is used if no clients for the region are configured.
+func (p *Poller) clientFor(resourceArn string) Client {
+	if len(p.clients) == 0 {
+		return p.client
+	}
+	parsed, err := arn.Parse(resourceArn)
+	if err != nil {
+		return p.client
+	}
+	if c, ok := p.clients[clientKey{accountID: parsed.AccountID, region: parsed.Region}]; ok {
+		return c
+	}
+	if c, ok := p.clients[clientKey{region: parsed.Region}]; ok {
+		return c
+	}
+	return p.client
+}
@@ -0,0 +1,106 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aereal/dynamodb-export-poller/internal/ddb"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/golang/mock/gomock"
+	"github.com/hashicorp/go-multierror"
+)
+
+func TestAccountRegion_validate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		accountRegion AccountRegion
+		wantErr       error
+	}{
+		{"region only", AccountRegion{Region: "eu-west-1"}, nil},
+		{"role and region", AccountRegion{RoleArn: "arn:aws:iam::210987654321:role/exporter", Region: "eu-west-1"}, nil},
+		{"no region", AccountRegion{RoleArn: "arn:aws:iam::210987654321:role/exporter"}, ErrAccountRegionRegionRequired},
+		{"malformed role", AccountRegion{RoleArn: "exporter", Region: "eu-west-1"}, &InvalidARNError{ARN: "exporter", Resource: "role", Reason: "arn: invalid prefix"}},
+		{"not a role", AccountRegion{RoleArn: "arn:aws:iam::210987654321:user/exporter", Region: "eu-west-1"}, &InvalidARNError{ARN: "arn:aws:iam::210987654321:user/exporter", Resource: "role", Reason: "must be an IAM role ARN"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assertErr(t, tc.accountRegion.validate(), tc.wantErr)
+		})
+	}
+}
+
+func TestNewPoller_accountRegions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := ddb.NewMockClient(ctrl)
+
+	options := PollerOptions{
+		Concurrency: 1,
+		AccountRegions: []AccountRegion{
+			{RoleArn: "arn:aws:iam::210987654321:role/exporter", Region: "eu-west-1"},
+			{Region: "ap-northeast-1"},
+		},
+	}
+	t.Run("clients built", func(t *testing.T) {
+		poller, err := NewPoller(options, WithClient(mockClient), WithAWSConfig(aws.Config{Region: "us-east-1"}))
+		if err != nil {
+			t.Fatalf("NewPoller(): %s", err)
+		}
+		if poller.client != mockClient {
+			t.Errorf("default client:\n\twant=%#v\n\tgot=%#v", mockClient, poller.client)
+		}
+		for _, key := range []clientKey{{accountID: "210987654321", region: "eu-west-1"}, {region: "ap-northeast-1"}} {
+			if _, ok := poller.clients[key].(*dynamodb.Client); !ok {
+				t.Errorf("client for %#v must be *dynamodb.Client but got %T", key, poller.clients[key])
+			}
+		}
+	})
+	t.Run("invalid account region", func(t *testing.T) {
+		_, err := NewPoller(PollerOptions{Concurrency: 1, AccountRegions: []AccountRegion{{}}}, WithClient(mockClient))
+		assertErr(t, err, multierror.Append(nil, ErrAccountRegionRegionRequired))
+	})
+}
+
+func TestPoller_clientFor(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	defaultClient := ddb.NewMockClient(ctrl)
+	accountClient := ddb.NewMockClient(ctrl)
+	regionClient := ddb.NewMockClient(ctrl)
+
+	defaultExportArn := "arn:aws:dynamodb:us-east-1:123456789012:table/my-table/export/1"
+	accountExportArn := "arn:aws:dynamodb:eu-west-1:210987654321:table/my-table/export/2"
+	regionExportArn := "arn:aws:dynamodb:ap-northeast-1:123456789012:table/my-table/export/3"
+	otherAccountExportArn := "arn:aws:dynamodb:eu-west-1:123456789012:table/my-table/export/4"
+	completed := func(exportArn string) *types.ExportDescription {
+		return &types.ExportDescription{ExportArn: aws.String(exportArn), ExportStatus: types.ExportStatusCompleted}
+	}
+	describeExportOf(defaultClient, defaultExportArn, completed(defaultExportArn)).Times(1)
+	describeExportOf(defaultClient, otherAccountExportArn, completed(otherAccountExportArn)).Times(1)
+	describeExportOf(accountClient, accountExportArn, completed(accountExportArn)).Times(1)
+	describeExportOf(regionClient, regionExportArn, completed(regionExportArn)).Times(1)
+
+	options := PollerOptions{
+		Concurrency: 2,
+		MaxAttempts: 1,
+		AccountRegions: []AccountRegion{
+			{RoleArn: "arn:aws:iam::210987654321:role/exporter", Region: "eu-west-1"},
+			{Region: "ap-northeast-1"},
+		},
+	}
+	poller, err := NewPoller(options,
+		WithClient(defaultClient),
+		WithRegionalClient("210987654321", "eu-west-1", accountClient),
+		WithRegionalClient("", "ap-northeast-1", regionClient),
+	)
+	if err != nil {
+		t.Fatalf("NewPoller(): %s", err)
+	}
+	_, err = poller.Poll(context.Background(), Targets{ExportArns: []string{defaultExportArn, accountExportArn, regionExportArn, otherAccountExportArn}})
+	assertErr(t, err, nil)
+}
@@ -0,0 +1,63 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/rs/zerolog/log"
+)
+
+var errNotEnoughExports = errors.New("not enough exports appeared")
+
+// ExportsNotAppearedError is an error that means fewer export jobs than PollerOptions.MinExports appeared on the table until PollerOptions.AppearanceTimeout.
+type ExportsNotAppearedError struct {
+	// TableArn is the ARN of the table
+	TableArn string
+
+	// Want is PollerOptions.MinExports
+	Want int
+
+	// Found is the number of the matched export jobs found last
+	Found int
+}
+
+func (e *ExportsNotAppearedError) Error() string {
+	return fmt.Sprintf("only %d of %d exports appeared on %s", e.Found, e.Want, e.TableArn)
+}
+
+// awaitExports lists exports on the table repeatedly until PollerOptions.MinExports export jobs matching PollerOptions.Filter appear.
+func (p *Poller) awaitExports(ctx context.Context, tableArn string) ([]string, error) {
+	appearCtx := ctx
+	if p.options.AppearanceTimeout > 0 {
+		var cancel context.CancelFunc
+		appearCtx, cancel = context.WithTimeout(ctx, p.options.AppearanceTimeout)
+		defer cancel()
+	}
+	var (
+		exportArns []string
+		listed     bool
+	)
+	err := retryWithBackoff(appearCtx, p.options.backoff(), 0, func() error {
+		arns, err := p.matchedExportArns(appearCtx, tableArn, false)
+		if err != nil {
+			// the requests have been retried already
+			return markPermanent(err)
+		}
+		exportArns, listed = arns, true
+		if len(exportArns) < p.options.MinExports {
+			log.Debug().Str("tableArn", tableArn).Int("found", len(exportArns)).Int("want", p.options.MinExports).Msg("waiting for exports to appear")
+			return errNotEnoughExports
+		}
+		return nil
+	})
+	switch {
+	case err == nil:
+		return exportArns, nil
+	// the deadline may come while waiting for the next attempt
+	case errors.Is(err, errNotEnoughExports) || (listed && errors.Is(err, context.DeadlineExceeded)):
+		return nil, &ExportsNotAppearedError{TableArn: tableArn, Want: p.options.MinExports, Found: len(exportArns)}
+	default:
+		return nil, err
+	}
+}
@@ -0,0 +1,143 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aereal/dynamodb-export-poller/internal/ddb"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/golang/mock/gomock"
+	"github.com/hashicorp/go-multierror"
+)
+
+func TestPoller_PollExportsOnTable_minExports(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	tableArn := "arn:aws:dynamodb:us-east-1:123456789012:table/my-table"
+	exportArn1 := tableArn + "/export/1"
+	exportArn2 := tableArn + "/export/2"
+	exportTime := time.Date(2023, time.October, 1, 0, 0, 0, 0, time.UTC)
+	description := func(exportArn string, status types.ExportStatus, exportTime time.Time) *types.ExportDescription {
+		return &types.ExportDescription{ExportArn: aws.String(exportArn), ExportStatus: status, ExportTime: aws.Time(exportTime)}
+	}
+	testCases := []struct {
+		name     string
+		options  PollerOptions
+		onMock   func(mockClient *ddb.MockClient)
+		wantArns []string
+		wantErr  error
+	}{
+		{
+			"export appears later",
+			PollerOptions{MinExports: 1, Filter: ExportFilter{ExportTimeAfter: exportTime}},
+			func(mockClient *ddb.MockClient) {
+				seq(
+					listExports(mockClient, nil).Times(1),
+					listExports(mockClient, []types.ExportSummary{{ExportArn: aws.String(exportArn1), ExportStatus: types.ExportStatusInProgress}}).Times(1),
+				)
+				describeExportOf(mockClient, exportArn1, description(exportArn1, types.ExportStatusCompleted, exportTime)).Times(2)
+			},
+			[]string{exportArn1},
+			nil,
+		},
+		{
+			"completed export counts",
+			PollerOptions{MinExports: 1, Filter: ExportFilter{ExportTimeAfter: exportTime}},
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{{ExportArn: aws.String(exportArn1), ExportStatus: types.ExportStatusCompleted}}).Times(1)
+				describeExportOf(mockClient, exportArn1, description(exportArn1, types.ExportStatusCompleted, exportTime)).Times(2)
+			},
+			[]string{exportArn1},
+			nil,
+		},
+		{
+			"filtered by export time",
+			PollerOptions{MinExports: 1, Filter: ExportFilter{ExportTimeAfter: exportTime}},
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{
+					{ExportArn: aws.String(exportArn1), ExportStatus: types.ExportStatusCompleted},
+					{ExportArn: aws.String(exportArn2), ExportStatus: types.ExportStatusInProgress},
+				}).Times(1)
+				describeExportOf(mockClient, exportArn1, description(exportArn1, types.ExportStatusCompleted, exportTime.Add(-time.Hour))).Times(1)
+				describeExportOf(mockClient, exportArn2, description(exportArn2, types.ExportStatusCompleted, exportTime)).Times(2)
+			},
+			[]string{exportArn2},
+			nil,
+		},
+		{
+			"appearance timeout",
+			PollerOptions{MinExports: 2, AppearanceTimeout: time.Millisecond * 20, Filter: ExportFilter{ExportTimeAfter: exportTime}},
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{{ExportArn: aws.String(exportArn1), ExportStatus: types.ExportStatusInProgress}}).MinTimes(1)
+				describeExportOf(mockClient, exportArn1, description(exportArn1, types.ExportStatusInProgress, exportTime)).MinTimes(1)
+			},
+			nil,
+			&ExportsNotAppearedError{TableArn: tableArn, Want: 2, Found: 1},
+		},
+		{
+			"ListExports error",
+			PollerOptions{MinExports: 1, Filter: ExportFilter{ExportTimeAfter: exportTime}},
+			func(mockClient *ddb.MockClient) {
+				mockClient.EXPECT().
+					ListExports(gomock.Any(), gomock.Any()).
+					Return(nil, &types.ResourceNotFoundException{Message: aws.String("table not found")}).
+					Times(1)
+			},
+			nil,
+			errors.New("ListExports(): ResourceNotFoundException: table not found"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := ddb.NewMockClient(ctrl)
+			tc.onMock(mockClient)
+			options := tc.options
+			options.Concurrency = 1
+			options.InitialDelay = time.Millisecond
+			options.MaxDelay = time.Millisecond * 5
+			options.MaxAttempts = 1
+			poller, err := NewPoller(options, WithClient(mockClient))
+			if err != nil {
+				t.Fatalf("NewPoller(): %s", err)
+			}
+			results, err := poller.PollExportsOnTable(context.Background(), tableArn)
+			assertErr(t, err, tc.wantErr)
+			var gotArns []string
+			for _, r := range results {
+				gotArns = append(gotArns, r.ExportArn)
+			}
+			if !reflect.DeepEqual(gotArns, tc.wantArns) {
+				t.Errorf("results:\n\twant=%v\n\tgot=%v", tc.wantArns, gotArns)
+			}
+		})
+	}
+}
+
+func TestNewPoller_minExports(t *testing.T) {
+	testCases := []struct {
+		name    string
+		options PollerOptions
+		want    error
+	}{
+		{"negative", PollerOptions{Concurrency: 1, MinExports: -1}, multierror.Append(nil, ErrMinExportsMustNotBeNegative)},
+		{"without filter", PollerOptions{Concurrency: 1, MinExports: 1}, multierror.Append(nil, ErrMinExportsRequiresNewExportsFilter)},
+		{"filtered by other than new exports", PollerOptions{Concurrency: 1, MinExports: 1, Filter: ExportFilter{S3Bucket: "my-bucket"}}, multierror.Append(nil, ErrMinExportsRequiresNewExportsFilter)},
+		{"started after", PollerOptions{Concurrency: 1, MinExports: 1, Filter: ExportFilter{StartedAfter: time.Now()}}, nil},
+		{"export time after", PollerOptions{Concurrency: 1, MinExports: 1, Filter: ExportFilter{ExportTimeAfter: time.Now()}}, nil},
+		{"client token", PollerOptions{Concurrency: 1, MinExports: 1, Filter: ExportFilter{ClientToken: "token"}}, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewPoller(tc.options, WithClient(&ddb.MockClient{}))
+			assertErr(t, err, tc.want)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package ddbexportpoller
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+)
+
+var tableNamePattern = regexp.MustCompile(`\A[a-zA-Z0-9_.-]{3,255}\z`)
+
+// InvalidARNError is an error that means the ARN is malformed or does not point to the expected DynamoDB resource.
+//
+// It wraps ErrTableArnRequired or ErrExportArnRequired if the expected resource is a table or an export.
+type InvalidARNError struct {
+	// ARN is the given string
+	ARN string
+
+	// Resource is the expected resource type such as "table", "export" or "role"
+	Resource string
+
+	// Reason describes why the ARN is invalid
+	Reason string
+}
+
+func (e *InvalidARNError) Error() string {
+	return fmt.Sprintf("invalid %s ARN %q: %s", e.Resource, e.ARN, e.Reason)
+}
+
+func (e *InvalidARNError) Unwrap() error {
+	switch e.Resource {
+	case "table":
+		return ErrTableArnRequired
+	case "export":
+		return ErrExportArnRequired
+	default:
+		return nil
+	}
+}
+
+// TableARN is a parsed ARN of a DynamoDB table.
+type TableARN struct {
+	// Partition is the AWS partition such as aws, aws-cn
+	Partition string
+
+	// Region is the region the table belongs to
+	Region string
+
+	// AccountID is the ID of the AWS account that owns the table
+	AccountID string
+
+	// TableName is the name of the table
+	TableName string
+}
+
+// ParseTableARN parses s as an ARN of a DynamoDB table.
+//
+// ErrTableArnRequired is returned if s is empty, and InvalidARNError is returned if s is not a table ARN.
+func ParseTableARN(s string) (TableARN, error) {
+	if s == "" {
+		return TableARN{}, ErrTableArnRequired
+	}
+	parsed, segments, err := parseDynamoDBARN(s)
+	if err != nil {
+		return TableARN{}, &InvalidARNError{ARN: s, Resource: "table", Reason: err.Error()}
+	}
+	if len(segments) != 2 {
+		return TableARN{}, &InvalidARNError{ARN: s, Resource: "table", Reason: "resource must be table/TABLE_NAME"}
+	}
+	return newTableARN(parsed, segments[1]), nil
+}
+
+func (a TableARN) String() string {
+	return arn.ARN{
+		Partition: a.Partition,
+		Service:   "dynamodb",
+		Region:    a.Region,
+		AccountID: a.AccountID,
+		Resource:  "table/" + a.TableName,
+	}.String()
+}
+
+// ExportARN is a parsed ARN of a DynamoDB export job.
+type ExportARN struct {
+	// Table is the ARN of the exported table
+	Table TableARN
+
+	// ExportID is the ID of the export job
+	ExportID string
+}
+
+// ParseExportARN parses s as an ARN of a DynamoDB export job.
+//
+// ErrExportArnRequired is returned if s is empty, and InvalidARNError is returned if s is not an export ARN.
+func ParseExportARN(s string) (ExportARN, error) {
+	if s == "" {
+		return ExportARN{}, ErrExportArnRequired
+	}
+	parsed, segments, err := parseDynamoDBARN(s)
+	if err != nil {
+		return ExportARN{}, &InvalidARNError{ARN: s, Resource: "export", Reason: err.Error()}
+	}
+	if len(segments) != 4 || segments[2] != "export" || segments[3] == "" {
+		return ExportARN{}, &InvalidARNError{ARN: s, Resource: "export", Reason: "resource must be table/TABLE_NAME/export/EXPORT_ID"}
+	}
+	return ExportARN{Table: newTableARN(parsed, segments[1]), ExportID: segments[3]}, nil
+}
+
+func (a ExportARN) String() string {
+	return a.Table.String() + "/export/" + a.ExportID
+}
+
+func newTableARN(parsed arn.ARN, tableName string) TableARN {
+	return TableARN{
+		Partition: parsed.Partition,
+		Region:    parsed.Region,
+		AccountID: parsed.AccountID,
+		TableName: tableName,
+	}
+}
+
+// parseDynamoDBARN parses s as an ARN of a DynamoDB table or its sub-resource and returns the resource segments.
+func parseDynamoDBARN(s string) (arn.ARN, []string, error) {
+	parsed, err := arn.Parse(s)
+	if err != nil {
+		return arn.ARN{}, nil, err
+	}
+	if parsed.Service != "dynamodb" {
+		return arn.ARN{}, nil, fmt.Errorf("service must be dynamodb but got %q", parsed.Service)
+	}
+	if parsed.Region == "" || parsed.AccountID == "" {
+		return arn.ARN{}, nil, errors.New("region and account ID are required")
+	}
+	segments := strings.Split(parsed.Resource, "/")
+	if segments[0] != "table" || len(segments) < 2 {
+		return arn.ARN{}, nil, fmt.Errorf("resource type must be table but got %q", parsed.Resource)
+	}
+	if !tableNamePattern.MatchString(segments[1]) {
+		return arn.ARN{}, nil, fmt.Errorf("invalid table name %q", segments[1])
+	}
+	return parsed, segments, nil
+}
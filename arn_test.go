@@ -0,0 +1,73 @@
+package ddbexportpoller
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseTableARN(t *testing.T) {
+	testCases := []struct {
+		name    string
+		s       string
+		want    TableARN
+		wantErr error
+	}{
+		{"ok", "arn:aws:dynamodb:us-east-1:123456789012:table/my-table", TableARN{Partition: "aws", Region: "us-east-1", AccountID: "123456789012", TableName: "my-table"}, nil},
+		{"other partition", "arn:aws-cn:dynamodb:cn-north-1:123456789012:table/my_table.v2", TableARN{Partition: "aws-cn", Region: "cn-north-1", AccountID: "123456789012", TableName: "my_table.v2"}, nil},
+		{"empty", "", TableARN{}, ErrTableArnRequired},
+		{"table name", "my-table", TableARN{}, &InvalidARNError{ARN: "my-table", Resource: "table", Reason: "arn: invalid prefix"}},
+		{"other service", "arn:aws:s3:us-east-1:123456789012:table/my-table", TableARN{}, &InvalidARNError{ARN: "arn:aws:s3:us-east-1:123456789012:table/my-table", Resource: "table", Reason: `service must be dynamodb but got "s3"`}},
+		{"no region", "arn:aws:dynamodb::123456789012:table/my-table", TableARN{}, &InvalidARNError{ARN: "arn:aws:dynamodb::123456789012:table/my-table", Resource: "table", Reason: "region and account ID are required"}},
+		{"other resource", "arn:aws:dynamodb:us-east-1:123456789012:global-table/my-table", TableARN{}, &InvalidARNError{ARN: "arn:aws:dynamodb:us-east-1:123456789012:global-table/my-table", Resource: "table", Reason: `resource type must be table but got "global-table/my-table"`}},
+		{"invalid table name", "arn:aws:dynamodb:us-east-1:123456789012:table/a", TableARN{}, &InvalidARNError{ARN: "arn:aws:dynamodb:us-east-1:123456789012:table/a", Resource: "table", Reason: `invalid table name "a"`}},
+		{"export ARN", "arn:aws:dynamodb:us-east-1:123456789012:table/my-table/export/1234-5678", TableARN{}, &InvalidARNError{ARN: "arn:aws:dynamodb:us-east-1:123456789012:table/my-table/export/1234-5678", Resource: "table", Reason: "resource must be table/TABLE_NAME"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseTableARN(tc.s)
+			assertErr(t, err, tc.wantErr)
+			if tc.wantErr != nil && !errors.Is(err, ErrTableArnRequired) {
+				t.Errorf("expected error wrapping ErrTableArnRequired but got %#v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("TableARN:\n\twant=%#v\n\tgot=%#v", tc.want, got)
+			}
+			if tc.wantErr == nil && got.String() != tc.s {
+				t.Errorf("String():\n\twant=%s\n\tgot=%s", tc.s, got.String())
+			}
+		})
+	}
+}
+
+func TestParseExportARN(t *testing.T) {
+	table := TableARN{Partition: "aws", Region: "us-east-1", AccountID: "123456789012", TableName: "my-table"}
+	testCases := []struct {
+		name    string
+		s       string
+		want    ExportARN
+		wantErr error
+	}{
+		{"ok", "arn:aws:dynamodb:us-east-1:123456789012:table/my-table/export/1234-5678", ExportARN{Table: table, ExportID: "1234-5678"}, nil},
+		{"empty", "", ExportARN{}, ErrExportArnRequired},
+		{"table ARN", "arn:aws:dynamodb:us-east-1:123456789012:table/my-table", ExportARN{}, &InvalidARNError{ARN: "arn:aws:dynamodb:us-east-1:123456789012:table/my-table", Resource: "export", Reason: "resource must be table/TABLE_NAME/export/EXPORT_ID"}},
+		{"stream ARN", "arn:aws:dynamodb:us-east-1:123456789012:table/my-table/stream/2023-10-01", ExportARN{}, &InvalidARNError{ARN: "arn:aws:dynamodb:us-east-1:123456789012:table/my-table/stream/2023-10-01", Resource: "export", Reason: "resource must be table/TABLE_NAME/export/EXPORT_ID"}},
+		{"empty export ID", "arn:aws:dynamodb:us-east-1:123456789012:table/my-table/export/", ExportARN{}, &InvalidARNError{ARN: "arn:aws:dynamodb:us-east-1:123456789012:table/my-table/export/", Resource: "export", Reason: "resource must be table/TABLE_NAME/export/EXPORT_ID"}},
+		{"other service", "arn:aws:s3:::my-bucket", ExportARN{}, &InvalidARNError{ARN: "arn:aws:s3:::my-bucket", Resource: "export", Reason: `service must be dynamodb but got "s3"`}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParseExportARN(tc.s)
+			assertErr(t, err, tc.wantErr)
+			if tc.wantErr != nil && !errors.Is(err, ErrExportArnRequired) {
+				t.Errorf("expected error wrapping ErrExportArnRequired but got %#v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ExportARN:\n\twant=%#v\n\tgot=%#v", tc.want, got)
+			}
+			if tc.wantErr == nil && got.String() != tc.s {
+				t.Errorf("String():\n\twant=%s\n\tgot=%s", tc.s, got.String())
+			}
+		})
+	}
+}
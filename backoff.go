@@ -0,0 +1,212 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// ErrUnknownBackoffStrategy is an error that means the name of BackoffStrategy is unknown
+var ErrUnknownBackoffStrategy = errors.New("unknown backoff strategy")
+
+// BackoffStrategy decides the delay before the Poller sends the request again.
+//
+// The methods may be called concurrently from multiple goroutines.
+type BackoffStrategy interface {
+	// Delay returns the delay before the retry.
+	//
+	// The retry starts from 1, and prev is the delay returned for the previous retry or zero for the first retry.
+	Delay(retry int, prev time.Duration) time.Duration
+}
+
+// Names of the built-in BackoffStrategy implementations accepted by NewBackoffStrategy
+const (
+	BackoffConstant           = "constant"
+	BackoffLinear             = "linear"
+	BackoffExponential        = "exponential"
+	BackoffFullJitter         = "full-jitter"
+	BackoffDecorrelatedJitter = "decorrelated-jitter"
+)
+
+// NewBackoffStrategy returns the built-in BackoffStrategy by its name.
+//
+// ErrUnknownBackoffStrategy is returned if the name is unknown.
+func NewBackoffStrategy(name string, initialDelay, maxDelay time.Duration) (BackoffStrategy, error) {
+	switch name {
+	case BackoffConstant:
+		return ConstantBackoff{Interval: initialDelay}, nil
+	case BackoffLinear:
+		return LinearBackoff{InitialDelay: initialDelay, MaxDelay: maxDelay}, nil
+	case BackoffExponential:
+		return ExponentialBackoff{InitialDelay: initialDelay, MaxDelay: maxDelay}, nil
+	case BackoffFullJitter:
+		return FullJitterBackoff{InitialDelay: initialDelay, MaxDelay: maxDelay}, nil
+	case BackoffDecorrelatedJitter:
+		return DecorrelatedJitterBackoff{InitialDelay: initialDelay, MaxDelay: maxDelay}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownBackoffStrategy, name)
+	}
+}
+
+// ConstantBackoff waits the same delay before each retry.
+type ConstantBackoff struct {
+	Interval time.Duration
+}
+
+var _ BackoffStrategy = ConstantBackoff{}
+
+func (b ConstantBackoff) Delay(retry int, prev time.Duration) time.Duration {
+	return b.Interval
+}
+
+// LinearBackoff increases the delay by InitialDelay for each retry up to MaxDelay.
+type LinearBackoff struct {
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+}
+
+var _ BackoffStrategy = LinearBackoff{}
+
+func (b LinearBackoff) Delay(retry int, prev time.Duration) time.Duration {
+	maxDelay := capDelay(b.InitialDelay, b.MaxDelay)
+	if b.InitialDelay > 0 && time.Duration(retry) > maxDelay/b.InitialDelay {
+		return maxDelay
+	}
+	return b.InitialDelay * time.Duration(retry)
+}
+
+// ExponentialBackoff doubles the delay from InitialDelay for each retry up to MaxDelay.
+//
+// It is the default BackoffStrategy of the Poller.
+type ExponentialBackoff struct {
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+}
+
+var _ BackoffStrategy = ExponentialBackoff{}
+
+func (b ExponentialBackoff) Delay(retry int, prev time.Duration) time.Duration {
+	return exponentialDelay(b.InitialDelay, b.MaxDelay, retry)
+}
+
+// FullJitterBackoff waits a random delay between zero and the delay of ExponentialBackoff.
+//
+// See https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/
+type FullJitterBackoff struct {
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+}
+
+var _ BackoffStrategy = FullJitterBackoff{}
+
+func (b FullJitterBackoff) Delay(retry int, prev time.Duration) time.Duration {
+	return randomDelay(0, exponentialDelay(b.InitialDelay, b.MaxDelay, retry))
+}
+
+// DecorrelatedJitterBackoff waits a random delay between InitialDelay and three times the previous delay up to MaxDelay.
+//
+// See https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/
+type DecorrelatedJitterBackoff struct {
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+}
+
+var _ BackoffStrategy = DecorrelatedJitterBackoff{}
+
+func (b DecorrelatedJitterBackoff) Delay(retry int, prev time.Duration) time.Duration {
+	if prev < b.InitialDelay {
+		prev = b.InitialDelay
+	}
+	delay := randomDelay(b.InitialDelay, prev*3)
+	if maxDelay := capDelay(b.InitialDelay, b.MaxDelay); delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
+
+// capDelay returns maxDelay, or initialDelay if maxDelay is less than it.
+func capDelay(initialDelay, maxDelay time.Duration) time.Duration {
+	if maxDelay < initialDelay {
+		return initialDelay
+	}
+	return maxDelay
+}
+
+func exponentialDelay(initialDelay, maxDelay time.Duration, retry int) time.Duration {
+	maxDelay = capDelay(initialDelay, maxDelay)
+	delay := initialDelay
+	for i := 1; i < retry && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
+
+var (
+	randMux sync.Mutex
+	rnd     = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// randomDelay returns a random delay in [min, max).
+func randomDelay(min, max time.Duration) time.Duration {
+	if max <= min {
+		return min
+	}
+	randMux.Lock()
+	defer randMux.Unlock()
+	return min + time.Duration(rnd.Int63n(int64(max-min)))
+}
+
+// permanentError is an error that stops retrying.
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+func (e *permanentError) Temporary() bool {
+	return false
+}
+
+func markPermanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// retryWithBackoff calls f until it succeeds, waiting between the attempts as the strategy says.
+//
+// It gives up if f returns an error marked by markPermanent or the error whose Temporary() returns false, maxAttempts is reached, or the context is done.
+// Zero maxAttempts means no limit.
+func retryWithBackoff(ctx context.Context, strategy BackoffStrategy, maxAttempts int, f func() error) error {
+	var delay time.Duration
+	for attempt := 1; ; attempt++ {
+		err := f()
+		if err == nil {
+			return nil
+		}
+		if perm, ok := err.(*permanentError); ok {
+			return perm.err
+		}
+		var temporary interface{ Temporary() bool }
+		if errors.As(err, &temporary) && !temporary.Temporary() {
+			return err
+		}
+		if maxAttempts > 0 && attempt >= maxAttempts {
+			return err
+		}
+		delay = strategy.Delay(attempt, delay)
+		if err := sleepContext(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aereal/dynamodb-export-poller/internal/ddb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/golang/mock/gomock"
+)
+
+func TestNewBackoffStrategy(t *testing.T) {
+	testCases := []struct {
+		name    string
+		want    BackoffStrategy
+		wantErr bool
+	}{
+		{BackoffConstant, ConstantBackoff{Interval: time.Second}, false},
+		{BackoffLinear, LinearBackoff{InitialDelay: time.Second, MaxDelay: time.Minute}, false},
+		{BackoffExponential, ExponentialBackoff{InitialDelay: time.Second, MaxDelay: time.Minute}, false},
+		{BackoffFullJitter, FullJitterBackoff{InitialDelay: time.Second, MaxDelay: time.Minute}, false},
+		{BackoffDecorrelatedJitter, DecorrelatedJitterBackoff{InitialDelay: time.Second, MaxDelay: time.Minute}, false},
+		{"fibonacci", nil, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := NewBackoffStrategy(tc.name, time.Second, time.Minute)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("error: want error=%v but got %v", tc.wantErr, err)
+			}
+			if err != nil && !errors.Is(err, ErrUnknownBackoffStrategy) {
+				t.Errorf("want ErrUnknownBackoffStrategy but got %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("strategy:\n\twant=%#v\n\tgot=%#v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestBackoffStrategy_Delay(t *testing.T) {
+	testCases := []struct {
+		name     string
+		strategy BackoffStrategy
+		want     []time.Duration
+	}{
+		{"constant", ConstantBackoff{Interval: time.Second}, []time.Duration{time.Second, time.Second, time.Second}},
+		{"linear", LinearBackoff{InitialDelay: time.Second, MaxDelay: 3 * time.Second}, []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second}},
+		{"exponential", ExponentialBackoff{InitialDelay: time.Second, MaxDelay: 5 * time.Second}, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second}},
+		{"exponential; max delay less than initial delay", ExponentialBackoff{InitialDelay: time.Second}, []time.Duration{time.Second, time.Second}},
+		{"no delay", ExponentialBackoff{}, []time.Duration{0, 0}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := make([]time.Duration, len(tc.want))
+			var prev time.Duration
+			for i := range got {
+				got[i] = tc.strategy.Delay(i+1, prev)
+				prev = got[i]
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("delays:\n\twant=%v\n\tgot=%v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestBackoffStrategy_Delay_jitter(t *testing.T) {
+	testCases := []struct {
+		name     string
+		strategy BackoffStrategy
+		// bounds returns the range [min, max] of the delay
+		bounds func(retry int, prev time.Duration) (time.Duration, time.Duration)
+	}{
+		{
+			"full jitter",
+			FullJitterBackoff{InitialDelay: time.Second, MaxDelay: 10 * time.Second},
+			func(retry int, prev time.Duration) (time.Duration, time.Duration) {
+				return 0, exponentialDelay(time.Second, 10*time.Second, retry)
+			},
+		},
+		{
+			"decorrelated jitter",
+			DecorrelatedJitterBackoff{InitialDelay: time.Second, MaxDelay: 10 * time.Second},
+			func(retry int, prev time.Duration) (time.Duration, time.Duration) {
+				if prev < time.Second {
+					prev = time.Second
+				}
+				max := prev * 3
+				if max > 10*time.Second {
+					max = 10 * time.Second
+				}
+				return time.Second, max
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for n := 0; n < 100; n++ {
+				var prev time.Duration
+				for retry := 1; retry <= 8; retry++ {
+					got := tc.strategy.Delay(retry, prev)
+					min, max := tc.bounds(retry, prev)
+					if got < min || got > max {
+						t.Fatalf("retry %d after %s: want between %s and %s but got %s", retry, prev, min, max, got)
+					}
+					prev = got
+				}
+			}
+		})
+	}
+}
+
+func TestRetryWithBackoff(t *testing.T) {
+	errTemporary := errors.New("temporary")
+	errPermanent := errors.New("permanent")
+	testCases := []struct {
+		name         string
+		maxAttempts  int
+		errs         []error
+		wantAttempts int
+		want         error
+	}{
+		{"succeeded at first", 3, []error{nil}, 1, nil},
+		{"succeeded after retries", 3, []error{errTemporary, errTemporary, nil}, 3, nil},
+		{"max attempts reached", 2, []error{errTemporary, errTemporary, nil}, 2, errTemporary},
+		{"permanent error", 3, []error{errTemporary, markPermanent(errPermanent), nil}, 2, errPermanent},
+		{"temporary error", 3, []error{&temporaryError{err: errTemporary}, nil}, 2, nil},
+		{"no limit", 0, []error{errTemporary, errTemporary, errTemporary, errTemporary, nil}, 5, nil},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var delays []time.Duration
+			strategy := backoffFunc(func(retry int, prev time.Duration) time.Duration {
+				delays = append(delays, prev)
+				return time.Duration(retry) * time.Millisecond
+			})
+			attempts := 0
+			err := retryWithBackoff(context.Background(), strategy, tc.maxAttempts, func() error {
+				err := tc.errs[attempts]
+				attempts++
+				return err
+			})
+			assertErr(t, err, tc.want)
+			if attempts != tc.wantAttempts {
+				t.Errorf("attempts: want=%d got=%d", tc.wantAttempts, attempts)
+			}
+			for i, prev := range delays {
+				if want := time.Duration(i) * time.Millisecond; prev != want {
+					t.Errorf("prev delay of retry %d: want=%s got=%s", i+1, want, prev)
+				}
+			}
+		})
+	}
+
+	t.Run("context done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		attempts := 0
+		err := retryWithBackoff(ctx, ConstantBackoff{Interval: time.Hour}, 0, func() error {
+			attempts++
+			cancel()
+			return errTemporary
+		})
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("want context.Canceled but got %v", err)
+		}
+		if attempts != 1 {
+			t.Errorf("attempts: want=1 got=%d", attempts)
+		}
+	})
+}
+
+func TestPoller_PollExport_backoff(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := ddb.NewMockClient(ctrl)
+	seq(
+		describeExport(mockClient, &types.ExportDescription{ExportStatus: types.ExportStatusInProgress}).Times(2),
+		describeExport(mockClient, &types.ExportDescription{ExportStatus: types.ExportStatusCompleted}).Times(1),
+	)
+	var retries []int
+	strategy := backoffFunc(func(retry int, prev time.Duration) time.Duration {
+		retries = append(retries, retry)
+		return 0
+	})
+	poller, err := NewPoller(PollerOptions{Concurrency: 1, InitialDelay: time.Hour, Backoff: strategy}, WithClient(mockClient))
+	if err != nil {
+		t.Fatalf("NewPoller(): %s", err)
+	}
+	if _, err := poller.PollExport(context.Background(), "arn:aws:dynamodb:us-east-1:123456789012:table/my-table/export/1234-5678"); err != nil {
+		t.Fatalf("PollExport(): %s", err)
+	}
+	if want := []int{1, 2}; !reflect.DeepEqual(retries, want) {
+		t.Errorf("retries:\n\twant=%v\n\tgot=%v", want, retries)
+	}
+}
+
+type backoffFunc func(retry int, prev time.Duration) time.Duration
+
+func (f backoffFunc) Delay(retry int, prev time.Duration) time.Duration {
+	return f(retry, prev)
+}
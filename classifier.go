@@ -0,0 +1,174 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsretry "github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go"
+)
+
+// ErrorClass tells the Poller how to deal with an error of DynamoDB API request.
+type ErrorClass int
+
+const (
+	// ErrorClassRetryable means the request is sent again after the usual delay
+	ErrorClassRetryable ErrorClass = iota
+
+	// ErrorClassPermanent means the Poller stops polling the export job and returns the error
+	ErrorClassPermanent
+
+	// ErrorClassBackoffHarder means the request is sent again after a longer delay than usual, e.g. the request was throttled
+	ErrorClassBackoffHarder
+)
+
+func (c ErrorClass) String() string {
+	switch c {
+	case ErrorClassRetryable:
+		return "retryable"
+	case ErrorClassPermanent:
+		return "permanent"
+	case ErrorClassBackoffHarder:
+		return "backoff-harder"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrorClassifier classifies errors of DynamoDB API requests the Poller sends while polling export jobs.
+//
+// The methods may be called concurrently from multiple goroutines.
+type ErrorClassifier interface {
+	Classify(err error) ErrorClass
+}
+
+// ErrorClassifierFunc is an adapter to use an ordinary function as ErrorClassifier.
+type ErrorClassifierFunc func(err error) ErrorClass
+
+var _ ErrorClassifier = ErrorClassifierFunc(nil)
+
+func (f ErrorClassifierFunc) Classify(err error) ErrorClass {
+	return f(err)
+}
+
+var (
+	throttlingErrorCodes = map[string]bool{
+		"ThrottlingException":                    true,
+		"Throttling":                             true,
+		"LimitExceededException":                 true,
+		"ProvisionedThroughputExceededException": true,
+		"RequestLimitExceeded":                   true,
+		"RequestThrottled":                       true,
+		"RequestThrottledException":              true,
+		"TooManyRequestsException":               true,
+	}
+
+	retryableErrorCodes = map[string]bool{
+		"InternalServerError":            true,
+		"ServiceUnavailable":             true,
+		"RequestTimeout":                 true,
+		"RequestTimeoutException":        true,
+		"TransactionInProgressException": true,
+	}
+)
+
+// DefaultErrorClassifier is the ErrorClassifier used if PollerOptions.ErrorClassifier is not given.
+//
+// It classifies errors as below:
+//   - the request did not respond within PollerOptions.RequestTimeout: retryable
+//   - the context is canceled or its deadline exceeded: permanent
+//   - throttling errors such as ThrottlingException, LimitExceededException and ProvisionedThroughputExceededException: backoff-harder
+//   - transient errors such as InternalServerError and RequestTimeout: retryable
+//   - transient network errors such as connection reset and dial timeout: retryable
+//   - other network errors such as unknown host: permanent
+//   - other errors caused by the client such as ValidationException and AccessDeniedException: permanent
+//   - any other errors including server faults: retryable
+type DefaultErrorClassifier struct{}
+
+var _ ErrorClassifier = DefaultErrorClassifier{}
+
+func (DefaultErrorClassifier) Classify(err error) ErrorClass {
+	var reqTimeoutErr *RequestTimeoutError
+	if errors.As(err, &reqTimeoutErr) {
+		return ErrorClassRetryable
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return ErrorClassPermanent
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		if throttlingErrorCodes[apiErr.ErrorCode()] {
+			return ErrorClassBackoffHarder
+		}
+		if retryableErrorCodes[apiErr.ErrorCode()] {
+			return ErrorClassRetryable
+		}
+	}
+	switch (awsretry.RetryableConnectionError{}).IsErrorRetryable(err) {
+	case aws.TrueTernary:
+		return ErrorClassRetryable
+	case aws.FalseTernary:
+		return ErrorClassPermanent
+	}
+	if apiErr != nil && apiErr.ErrorFault() == smithy.FaultClient {
+		return ErrorClassPermanent
+	}
+	return ErrorClassRetryable
+}
+
+// temporaryError marks the error as retryable even if the error itself says it is not temporary, such as a network error.
+type temporaryError struct {
+	err error
+}
+
+func (e *temporaryError) Error() string {
+	return e.err.Error()
+}
+
+func (e *temporaryError) Unwrap() error {
+	return e.err
+}
+
+func (e *temporaryError) Temporary() bool {
+	return true
+}
+
+// classifyError marks err as permanent or retryable by PollerOptions.ErrorClassifier.
+//
+// It waits longer before returning if err is classified as backoff-harder, and the consecutive throttles are counted up to lengthen the wait.
+func (p *Poller) classifyError(ctx context.Context, err error, throttles *int) error {
+	switch p.options.errorClassifier().Classify(err) {
+	case ErrorClassPermanent:
+		return markPermanent(err)
+	case ErrorClassBackoffHarder:
+		*throttles++
+		_ = sleepContext(ctx, p.options.throttleDelay(*throttles))
+	default:
+		*throttles = 0
+	}
+	return &temporaryError{err: err}
+}
+
+// throttleDelay returns the extra delay after the consecutive throttles.
+//
+// It is the delay the configured BackoffStrategy gives to the retry one step further than the throttles, so that it grows as the throttles continue.
+func (o PollerOptions) throttleDelay(throttles int) time.Duration {
+	return o.backoff().Delay(throttles+1, 0)
+}
+
+// sleepContext waits for the duration and returns the context error if the context is done before that.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,179 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/aereal/dynamodb-export-poller/internal/ddb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/golang/mock/gomock"
+)
+
+func TestDefaultErrorClassifier_Classify(t *testing.T) {
+	apiErr := func(code string, fault smithy.ErrorFault) error {
+		return &smithy.OperationError{
+			ServiceID:     "DynamoDB",
+			OperationName: "DescribeExport",
+			Err:           &smithy.GenericAPIError{Code: code, Message: "oops", Fault: fault},
+		}
+	}
+	testCases := []struct {
+		name string
+		err  error
+		want ErrorClass
+	}{
+		{"ThrottlingException", apiErr("ThrottlingException", smithy.FaultClient), ErrorClassBackoffHarder},
+		{"LimitExceededException", apiErr("LimitExceededException", smithy.FaultClient), ErrorClassBackoffHarder},
+		{"ProvisionedThroughputExceededException", apiErr("ProvisionedThroughputExceededException", smithy.FaultClient), ErrorClassBackoffHarder},
+		{"RequestLimitExceeded", apiErr("RequestLimitExceeded", smithy.FaultClient), ErrorClassBackoffHarder},
+		{"wrapped throttling", fmt.Errorf("DescribeExport(): %w", apiErr("ThrottlingException", smithy.FaultUnknown)), ErrorClassBackoffHarder},
+		{"InternalServerError", apiErr("InternalServerError", smithy.FaultServer), ErrorClassRetryable},
+		{"RequestTimeout", apiErr("RequestTimeout", smithy.FaultClient), ErrorClassRetryable},
+		{"server fault", apiErr("oops", smithy.FaultServer), ErrorClassRetryable},
+		{"ValidationException", apiErr("ValidationException", smithy.FaultClient), ErrorClassPermanent},
+		{"AccessDeniedException", apiErr("AccessDeniedException", smithy.FaultClient), ErrorClassPermanent},
+		{"connection reset", &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}, ErrorClassRetryable},
+		{"dial error", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("i/o timeout")}, ErrorClassRetryable},
+		{"unknown host", &net.DNSError{Err: "no such host", Name: "dynamodb.example.com", IsNotFound: true}, ErrorClassPermanent},
+		{"request timeout", &RequestTimeoutError{Operation: "DescribeExport", Timeout: time.Second, Err: context.DeadlineExceeded}, ErrorClassRetryable},
+		{"context canceled", context.Canceled, ErrorClassPermanent},
+		{"context deadline exceeded", fmt.Errorf("DescribeExport(): %w", context.DeadlineExceeded), ErrorClassPermanent},
+		{"unknown error", errors.New("oops"), ErrorClassRetryable},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := (DefaultErrorClassifier{}).Classify(tc.err); got != tc.want {
+				t.Errorf("want=%s got=%s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestPoller_PollExport_errorClassifier(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	exportArn := "arn:aws:dynamodb:us-east-1:123456789012:table/my-table/export/1234-5678"
+	describeExportError := func(mockClient *ddb.MockClient, err error) *gomock.Call {
+		return mockClient.EXPECT().
+			DescribeExport(gomock.Any(), gomock.Any()).
+			Return(nil, err)
+	}
+	throttled := &smithy.GenericAPIError{Code: "ThrottlingException", Message: "rate exceeded", Fault: smithy.FaultClient}
+	invalid := &smithy.GenericAPIError{Code: "ValidationException", Message: "invalid", Fault: smithy.FaultClient}
+	testCases := []struct {
+		name       string
+		classifier ErrorClassifier
+		onMock     func(mockClient *ddb.MockClient)
+		wantPolls  int
+		want       error
+	}{
+		{
+			"throttled request is retried",
+			nil,
+			func(mockClient *ddb.MockClient) {
+				seq(
+					describeExportError(mockClient, throttled).Times(2),
+					describeExport(mockClient, &types.ExportDescription{ExportStatus: types.ExportStatusCompleted}).Times(1),
+				)
+			},
+			3,
+			nil,
+		},
+		{
+			"network error is retried",
+			nil,
+			func(mockClient *ddb.MockClient) {
+				seq(
+					describeExportError(mockClient, &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}).Times(1),
+					describeExport(mockClient, &types.ExportDescription{ExportStatus: types.ExportStatusCompleted}).Times(1),
+				)
+			},
+			2,
+			nil,
+		},
+		{
+			"permanent error is not retried",
+			nil,
+			func(mockClient *ddb.MockClient) {
+				describeExportError(mockClient, invalid).Times(1)
+			},
+			1,
+			invalid,
+		},
+		{
+			"custom classifier",
+			ErrorClassifierFunc(func(err error) ErrorClass {
+				if errors.Is(err, invalid) {
+					return ErrorClassRetryable
+				}
+				return ErrorClassPermanent
+			}),
+			func(mockClient *ddb.MockClient) {
+				seq(
+					describeExportError(mockClient, invalid).Times(1),
+					describeExportError(mockClient, throttled).Times(1),
+				)
+			},
+			2,
+			throttled,
+		},
+		{
+			"gives up on throttling after max attempts",
+			nil,
+			func(mockClient *ddb.MockClient) {
+				describeExportError(mockClient, throttled).Times(3)
+			},
+			3,
+			throttled,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := ddb.NewMockClient(ctrl)
+			tc.onMock(mockClient)
+			poller, err := NewPoller(PollerOptions{Concurrency: 1, MaxAttempts: 3, ErrorClassifier: tc.classifier}, WithClient(mockClient))
+			if err != nil {
+				t.Fatalf("NewPoller(): %s", err)
+			}
+			result, err := poller.PollExport(context.Background(), exportArn)
+			assertErr(t, err, tc.want)
+			if result.Polls != tc.wantPolls {
+				t.Errorf("polls: want=%d got=%d", tc.wantPolls, result.Polls)
+			}
+		})
+	}
+}
+
+func TestPollerOptions_throttleDelay(t *testing.T) {
+	testCases := []struct {
+		name      string
+		options   PollerOptions
+		throttles int
+		want      time.Duration
+	}{
+		{"first throttle", PollerOptions{InitialDelay: time.Second, MaxDelay: time.Minute}, 1, 2 * time.Second},
+		{"consecutive throttles", PollerOptions{InitialDelay: time.Second, MaxDelay: time.Minute}, 3, 8 * time.Second},
+		{"capped by max delay", PollerOptions{InitialDelay: time.Second, MaxDelay: 5 * time.Second}, 10, 5 * time.Second},
+		{"max delay less than initial delay", PollerOptions{InitialDelay: time.Second}, 3, time.Second},
+		{"no delay", PollerOptions{}, 3, 0},
+		{"configured backoff", PollerOptions{InitialDelay: time.Second, MaxDelay: time.Minute, Backoff: LinearBackoff{InitialDelay: time.Second, MaxDelay: time.Minute}}, 3, 4 * time.Second},
+		{"constant backoff", PollerOptions{InitialDelay: time.Second, Backoff: ConstantBackoff{Interval: 3 * time.Second}}, 3, 3 * time.Second},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.options.throttleDelay(tc.throttles); got != tc.want {
+				t.Errorf("want=%s got=%s", tc.want, got)
+			}
+		})
+	}
+}
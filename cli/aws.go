@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+var errRoleArnRequired = errors.New("-role-arn is required if -external-id or -role-session-name specified")
+
+type awsConfigFlags struct {
+	region          string
+	profile         string
+	endpointURL     string
+	roleArn         string
+	externalID      string
+	roleSessionName string
+}
+
+func (f *awsConfigFlags) register(fls *flag.FlagSet) {
+	fls.StringVar(&f.region, "region", "", "AWS region (default: taken from the environment)")
+	fls.StringVar(&f.profile, "profile", "", "shared config profile name (default: taken from the environment)")
+	fls.StringVar(&f.endpointURL, "endpoint-url", "", "override endpoint URL of DynamoDB API (e.g. DynamoDB Local, LocalStack)")
+	fls.StringVar(&f.roleArn, "role-arn", "", "IAM role ARN to assume")
+	fls.StringVar(&f.externalID, "external-id", "", "external ID to assume the role")
+	fls.StringVar(&f.roleSessionName, "role-session-name", "", "session name to assume the role")
+}
+
+func (f *awsConfigFlags) validate() error {
+	if f.roleArn == "" && (f.externalID != "" || f.roleSessionName != "") {
+		return errRoleArnRequired
+	}
+	return nil
+}
+
+func (f *awsConfigFlags) load(ctx context.Context) (aws.Config, error) {
+	if err := f.validate(); err != nil {
+		return aws.Config{}, err
+	}
+	var optFns []func(*config.LoadOptions) error
+	if f.region != "" {
+		optFns = append(optFns, config.WithRegion(f.region))
+	}
+	if f.profile != "" {
+		optFns = append(optFns, config.WithSharedConfigProfile(f.profile))
+	}
+	if f.endpointURL != "" {
+		endpointURL := f.endpointURL
+		// only DynamoDB is redirected so that STS to assume roles is resolved as usual
+		resolver := aws.EndpointResolverWithOptionsFunc(func(service, region string, options ...interface{}) (aws.Endpoint, error) {
+			if service != dynamodb.ServiceID {
+				return aws.Endpoint{}, &aws.EndpointNotFoundError{}
+			}
+			return aws.Endpoint{URL: endpointURL, SigningRegion: region}, nil
+		})
+		optFns = append(optFns, config.WithEndpointResolverWithOptions(resolver))
+	}
+	cfg, err := config.LoadDefaultConfig(ctx, optFns...)
+	if err != nil {
+		return aws.Config{}, fmt.Errorf("LoadDefaultConfig(): %w", err)
+	}
+	if f.roleArn != "" {
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), f.roleArn, func(o *stscreds.AssumeRoleOptions) {
+			if f.externalID != "" {
+				o.ExternalID = aws.String(f.externalID)
+			}
+			if f.roleSessionName != "" {
+				o.RoleSessionName = f.roleSessionName
+			}
+		})
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+	return cfg, nil
+}
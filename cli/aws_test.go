@@ -0,0 +1,106 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+func TestAWSConfigFlags_load(t *testing.T) {
+	restore := setenv(t, map[string]string{
+		"AWS_ACCESS_KEY_ID":           "dummy",
+		"AWS_SECRET_ACCESS_KEY":       "dummy",
+		"AWS_CONFIG_FILE":             "/dev/null",
+		"AWS_SHARED_CREDENTIALS_FILE": "/dev/null",
+	})
+	defer restore()
+
+	testCases := []struct {
+		name         string
+		flags        awsConfigFlags
+		wantErr      error
+		wantRegion   string
+		wantEndpoint string
+		wantAssume   bool
+	}{
+		{"region", awsConfigFlags{region: "ap-northeast-1"}, nil, "ap-northeast-1", "", false},
+		{"endpoint", awsConfigFlags{region: "us-east-1", endpointURL: "http://localhost:8000"}, nil, "us-east-1", "http://localhost:8000", false},
+		{"assume role", awsConfigFlags{region: "us-east-1", endpointURL: "http://127.0.0.1:1", roleArn: "arn:aws:iam::123456789012:role/my-role", externalID: "ext", roleSessionName: "poller"}, nil, "us-east-1", "http://127.0.0.1:1", true},
+		{"external ID without role", awsConfigFlags{region: "us-east-1", externalID: "ext"}, errRoleArnRequired, "", "", false},
+		{"session name without role", awsConfigFlags{region: "us-east-1", roleSessionName: "poller"}, errRoleArnRequired, "", "", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg, err := tc.flags.load(context.Background())
+			if err != tc.wantErr {
+				t.Fatalf("error:\n\twant=%v\n\tgot=%v", tc.wantErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if cfg.Region != tc.wantRegion {
+				t.Errorf("region:\n\twant=%s\n\tgot=%s", tc.wantRegion, cfg.Region)
+			}
+			if tc.wantEndpoint != "" {
+				if cfg.EndpointResolverWithOptions == nil {
+					t.Fatal("endpoint resolver must be configured")
+				}
+				endpoint, err := cfg.EndpointResolverWithOptions.ResolveEndpoint("DynamoDB", cfg.Region)
+				if err != nil {
+					t.Fatalf("ResolveEndpoint(): %s", err)
+				}
+				if endpoint.URL != tc.wantEndpoint {
+					t.Errorf("endpoint:\n\twant=%s\n\tgot=%s", tc.wantEndpoint, endpoint.URL)
+				}
+				// other services such as STS to assume roles are resolved as usual
+				var notFound *aws.EndpointNotFoundError
+				if _, err := cfg.EndpointResolverWithOptions.ResolveEndpoint(sts.ServiceID, cfg.Region); !errors.As(err, &notFound) {
+					t.Errorf("STS endpoint must not be overridden but got error: %v", err)
+				}
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+			defer cancel()
+			creds, err := cfg.Credentials.Retrieve(ctx)
+			if tc.wantAssume {
+				// the dummy credentials cannot assume the role so retrieving credentials must fail if the role is going to be assumed
+				if err == nil {
+					t.Errorf("expected to fail to assume role but got credentials: %#v", creds)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Retrieve(): %s", err)
+			}
+			if creds.AccessKeyID != "dummy" {
+				t.Errorf("access key ID:\n\twant=%s\n\tgot=%s", "dummy", creds.AccessKeyID)
+			}
+		})
+	}
+}
+
+func setenv(t *testing.T, vars map[string]string) func() {
+	t.Helper()
+	origs := map[string]*string{}
+	for k, v := range vars {
+		if orig, ok := os.LookupEnv(k); ok {
+			origs[k] = &orig
+		} else {
+			origs[k] = nil
+		}
+		os.Setenv(k, v)
+	}
+	return func() {
+		for k, orig := range origs {
+			if orig == nil {
+				os.Unsetenv(k)
+				continue
+			}
+			os.Setenv(k, *orig)
+		}
+	}
+}
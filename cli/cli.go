@@ -1,91 +1,101 @@
 package cli
 
 import (
-	"context"
 	"flag"
+	"fmt"
 	"io"
-	"runtime"
-	"time"
+	"os"
+	"strings"
 
 	ddbexportpoller "github.com/aereal/dynamodb-export-poller"
-	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
-const (
-	statusOK int = iota
-	statusNG
-)
-
 var defaultWriter io.Writer
 
-func NewApp(out io.Writer) *App {
+// NewApp creates new App that writes command results to out and logs to errOut.
+func NewApp(out, errOut io.Writer) *App {
 	if out == nil {
 		out = defaultWriter
 	}
-	return &App{out: out}
+	if errOut == nil {
+		errOut = defaultWriter
+	}
+	return &App{in: os.Stdin, out: out, errOut: errOut}
 }
 
 type App struct {
-	out io.Writer
+	in     io.Reader
+	out    io.Writer
+	errOut io.Writer
+
+	// pollerOptions are passed to ddbexportpoller.NewPoller in addition to the options built from flags
+	pollerOptions []ddbexportpoller.Option
+}
+
+type command struct {
+	name     string
+	synopsis string
+	run      func(c *App, name string, args []string) int
+}
+
+var commands = []*command{
+	{"wait", "wait for in-progress exports on the table or the export to finish", (*App).runWait},
+	{"list", "list exports on the table", (*App).runList},
+	{"describe", "describe the export", (*App).runDescribe},
+	{"start", "start an export and wait for it to finish", (*App).runStart},
+	{"watch", "stream changes of exports on the table or the export", (*App).runWatch},
+	{"history", "show finished exports on the table in chronological order", (*App).runHistory},
 }
 
 func (c *App) Run(argv []string) int {
-	fls := flag.NewFlagSet(argv[0], flag.ContinueOnError)
-	fls.SetOutput(c.out)
-	log.Logger = log.Logger.Output(c.out)
-	opts := ddbexportpoller.PollerOptions{}
-	var (
-		debug     bool
-		tableArn  string
-		exportArn string
-	)
-	fls.StringVar(&tableArn, "table-arn", "", "table ARN to watch exports")
-	fls.StringVar(&exportArn, "export-arn", "", "export ARN to watch exports")
-	fls.BoolVar(&debug, "debug", false, "enable debug logging")
-	fls.DurationVar(&opts.InitialDelay, "initial-delay", time.Second, "initial wait time")
-	fls.DurationVar(&opts.MaxDelay, "max-delay", time.Second*10, "max wait time")
-	fls.Int64Var(&opts.Concurrency, "concurrency", int64(runtime.NumCPU()), "concurrency to run requests")
-	fls.IntVar(&opts.MaxAttempts, "max-attempts", 0, "max attempts (zero means forever)")
-	fls.DurationVar(&opts.Timeout, "timeout", 0, "global timeout (zero means waits forever)")
-	switch err := fls.Parse(argv[1:]); err {
-	case nil: // continue
-	case flag.ErrHelp:
+	log.Logger = log.Logger.Output(c.errOut)
+	// run wait command if no subcommand given to keep backward compatibility
+	if len(argv) < 2 || strings.HasPrefix(argv[1], "-") {
+		return c.runWait(argv[0], argv[1:])
+	}
+	name := argv[1]
+	if name == "help" {
+		c.usage(argv[0])
 		return statusOK
-	default: // error but not ErrHelp
-		log.Error().Err(err).Send()
-		return statusNG
 	}
-	if debug {
-		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd.run(c, argv[0]+" "+name, argv[2:])
+		}
 	}
+	log.Error().Str("command", name).Msg("unknown command")
+	c.usage(argv[0])
+	return statusInvalidArguments
+}
 
-	presentTableArn := tableArn != ""
-	presentExportArn := exportArn != ""
-	switch {
-	case presentTableArn && presentExportArn:
-		log.Error().Msg("either of one of -table-arn or -export-arn must be specified")
-		return statusNG
-	case !(presentTableArn || presentExportArn):
-		log.Error().Msg("neither -table-arn nor -export-arn specified")
-		return statusNG
+func (c *App) usage(name string) {
+	fmt.Fprintf(c.errOut, "Usage: %s <command> [flags]\n\nCommands:\n", name)
+	for _, cmd := range commands {
+		fmt.Fprintf(c.errOut, "  %-10s %s\n", cmd.name, cmd.synopsis)
 	}
+	fmt.Fprintf(c.errOut, "\nRun `%s <command> -help` to show flags of each command.\n", name)
+}
 
-	ctx := context.Background()
-	poller, err := ddbexportpoller.NewPoller(opts)
-	if err != nil {
-		log.Error().Err(err).Send()
-		return statusNG
-	}
-	if exportArn != "" {
-		if err := poller.PollExport(ctx, exportArn); err != nil {
-			log.Error().Err(err).Send()
-			return statusNG
-		}
+func (c *App) newFlagSet(name, usage string) *flag.FlagSet {
+	fls := flag.NewFlagSet(name, flag.ContinueOnError)
+	fls.SetOutput(c.errOut)
+	fls.Usage = func() {
+		fmt.Fprintf(c.errOut, "Usage: %s\n\n", usage)
+		fls.PrintDefaults()
 	}
-	if err := poller.PollExportsOnTable(ctx, tableArn); err != nil {
+	return fls
+}
+
+// parseFlags parses args and returns false with the exit status if the command should not continue.
+func parseFlags(fls *flag.FlagSet, args []string) (int, bool) {
+	switch err := fls.Parse(args); err {
+	case nil: // continue
+		return statusOK, true
+	case flag.ErrHelp:
+		return statusOK, false
+	default: // error but not ErrHelp
 		log.Error().Err(err).Send()
-		return statusNG
+		return statusInvalidArguments, false
 	}
-	return statusOK
 }
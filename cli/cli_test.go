@@ -2,27 +2,580 @@ package cli
 
 import (
 	"bytes"
+	"context"
+	"fmt"
+	"regexp"
 	"testing"
+	"time"
+
+	ddbexportpoller "github.com/aereal/dynamodb-export-poller"
+	"github.com/aereal/dynamodb-export-poller/internal/ddb"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/golang/mock/gomock"
+)
+
+const (
+	testTableArn  = "arn:aws:dynamodb:us-east-1:123456789012:table/my-table"
+	testExportArn = "arn:aws:dynamodb:us-east-1:123456789012:table/my-table/export/9012-3456"
+
+	testOtherTableArn  = "arn:aws:dynamodb:us-east-1:123456789012:table/other-table"
+	testOtherExportArn = "arn:aws:dynamodb:us-east-1:123456789012:table/other-table/export/3456-9012"
 )
 
 func TestCLI(t *testing.T) {
+	startTime := time.Date(2023, time.October, 1, 0, 0, 0, 0, time.UTC)
 	testCases := []struct {
 		name       string
 		argv       []string
+		onMock     func(mockClient *ddb.MockClient)
 		wantStatus int
+		wantOut    string
 	}{
-		{"neither tableArn or exportArn specified", []string{"me"}, statusNG},
-		{"both tableArn and exportArn specified", []string{"me", "-table-arn", "arn:aws:dynamodb:us-east-1:123456789012:table/my-table", "-export-arn", "arn:aws:dynamodb:us-east-1:123456789012:table/my-table/export/9012-3456"}, statusNG},
+		{"neither tableArn or exportArn specified", []string{"me"}, nil, statusInvalidArguments, ""},
+		{
+			"both tableArn and exportArn specified",
+			[]string{"me", "-table-arn", testTableArn, "-export-arn", testExportArn},
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{{ExportArn: aws.String(testExportArn), ExportStatus: types.ExportStatusInProgress}}).Times(1)
+				describeExport(mockClient, &types.ExportDescription{ExportStatus: types.ExportStatusCompleted}).Times(1)
+			},
+			statusOK,
+			"",
+		},
+		{"help", []string{"me", "help"}, nil, statusOK, ""},
+		{"unknown command", []string{"me", "oops"}, nil, statusInvalidArguments, ""},
+		{"wait: help", []string{"me", "wait", "-help"}, nil, statusOK, ""},
+		{"wait: neither tableArn or exportArn specified", []string{"me", "wait"}, nil, statusInvalidArguments, ""},
+		{
+			"wait: exportArn",
+			[]string{"me", "wait", "-export-arn", testExportArn},
+			func(mockClient *ddb.MockClient) {
+				describeExport(mockClient, &types.ExportDescription{ExportStatus: types.ExportStatusCompleted}).Times(1)
+			},
+			statusOK,
+			"",
+		},
+		{
+			"wait: tableArn",
+			[]string{"me", "wait", "-table-arn", testTableArn},
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{{ExportArn: aws.String(testExportArn), ExportStatus: types.ExportStatusInProgress}}).Times(1)
+				describeExport(mockClient, &types.ExportDescription{ExportStatus: types.ExportStatusFailed}).Times(1)
+			},
+			statusExportFailed,
+			"",
+		},
+		{"wait: invalid output", []string{"me", "wait", "-export-arn", testExportArn, "-output", "yaml"}, nil, statusInvalidArguments, ""},
+		{"wait: invalid template", []string{"me", "wait", "-export-arn", testExportArn, "-output", "go-template={{.ExportArn"}, nil, statusInvalidArguments, ""},
+		{
+			"wait: output text",
+			[]string{"me", "wait", "-export-arn", testExportArn, "-output", "text"},
+			func(mockClient *ddb.MockClient) {
+				describeExport(mockClient, waitedExport(startTime)).Times(1)
+			},
+			statusOK,
+			testExportArn + "\tCOMPLETED\ts3://my-bucket/exports/my-table\texports/my-table/manifest-summary.json\t42\n",
+		},
+		{
+			"wait: output go-template",
+			[]string{"me", "wait", "-table-arn", testTableArn, "-output", "go-template={{.S3URL}} {{.ItemCount}}"},
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{{ExportArn: aws.String(testExportArn), ExportStatus: types.ExportStatusInProgress}}).Times(1)
+				describeExport(mockClient, waitedExport(startTime)).Times(1)
+			},
+			statusOK,
+			"s3://my-bucket/exports/my-table 42\n",
+		},
+		{
+			"wait: table name",
+			[]string{"me", "wait", "-table", "my-table"},
+			func(mockClient *ddb.MockClient) {
+				mockClient.EXPECT().
+					DescribeTable(gomock.Any(), &dynamodb.DescribeTableInput{TableName: aws.String("my-table")}).
+					Return(&dynamodb.DescribeTableOutput{Table: &types.TableDescription{TableArn: aws.String(testTableArn)}}, nil).
+					Times(1)
+				listExports(mockClient, []types.ExportSummary{{ExportArn: aws.String(testExportArn), ExportStatus: types.ExportStatusInProgress}}).Times(1)
+				describeExport(mockClient, &types.ExportDescription{ExportStatus: types.ExportStatusCompleted}).Times(1)
+			},
+			statusOK,
+			"",
+		},
+		{
+			"wait: many targets",
+			[]string{"me", "wait", "-table", "my-table", "-table-arn", testTableArn + "," + testOtherTableArn, "-export-arn", testOtherExportArn, "-output", "text", testExportArn},
+			func(mockClient *ddb.MockClient) {
+				mockClient.EXPECT().
+					DescribeTable(gomock.Any(), gomock.Any()).
+					Return(&dynamodb.DescribeTableOutput{Table: &types.TableDescription{TableArn: aws.String(testTableArn)}}, nil).
+					Times(1)
+				listExports(mockClient, []types.ExportSummary{{ExportArn: aws.String(testExportArn), ExportStatus: types.ExportStatusInProgress}}).Times(2)
+				describeExportOf(mockClient, testExportArn, completedExport(startTime)).Times(1)
+				describeExportOf(mockClient, testOtherExportArn, &types.ExportDescription{ExportArn: aws.String(testOtherExportArn), ExportStatus: types.ExportStatusCompleted}).Times(1)
+			},
+			statusOK,
+			"",
+		},
+		{"wait: invalid tableArn", []string{"me", "wait", "-table-arn", "arn:aws:s3:::my-bucket"}, nil, statusInvalidArguments, ""},
+		{
+			"wait: table not found",
+			[]string{"me", "wait", "-table", "my-table"},
+			func(mockClient *ddb.MockClient) {
+				mockClient.EXPECT().DescribeTable(gomock.Any(), gomock.Any()).Return(nil, &smithy.GenericAPIError{Code: "ResourceNotFoundException", Fault: smithy.FaultClient}).Times(1)
+			},
+			statusNotFound,
+			"",
+		},
+		{
+			"wait: timed out while resolving the table name",
+			[]string{"me", "wait", "-table", "my-table", "-timeout", "50ms"},
+			func(mockClient *ddb.MockClient) {
+				mockClient.EXPECT().
+					DescribeTable(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, input *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+						<-ctx.Done()
+						return nil, ctx.Err()
+					}).
+					Times(1)
+			},
+			statusTimeout,
+			"",
+		},
+		{
+			"wait: discover tables",
+			[]string{"me", "wait", "-table-pattern", "my-*", "-table-tag", "env=prod"},
+			func(mockClient *ddb.MockClient) {
+				mockClient.EXPECT().
+					ListTables(gomock.Any(), gomock.Any()).
+					Return(&dynamodb.ListTablesOutput{TableNames: []string{"my-table", "other-table"}}, nil).
+					Times(1)
+				mockClient.EXPECT().
+					DescribeTable(gomock.Any(), &dynamodb.DescribeTableInput{TableName: aws.String("my-table")}).
+					Return(&dynamodb.DescribeTableOutput{Table: &types.TableDescription{TableArn: aws.String(testTableArn)}}, nil).
+					Times(1)
+				mockClient.EXPECT().
+					ListTagsOfResource(gomock.Any(), gomock.Any()).
+					Return(&dynamodb.ListTagsOfResourceOutput{Tags: []types.Tag{{Key: aws.String("env"), Value: aws.String("prod")}}}, nil).
+					Times(1)
+				listExports(mockClient, []types.ExportSummary{{ExportArn: aws.String(testExportArn), ExportStatus: types.ExportStatusInProgress}}).Times(1)
+				describeExport(mockClient, &types.ExportDescription{ExportStatus: types.ExportStatusCompleted}).Times(1)
+			},
+			statusOK,
+			"",
+		},
+		{
+			"wait: no tables discovered",
+			[]string{"me", "wait", "-table-regexp", "^stg-"},
+			func(mockClient *ddb.MockClient) {
+				mockClient.EXPECT().
+					ListTables(gomock.Any(), gomock.Any()).
+					Return(&dynamodb.ListTablesOutput{TableNames: []string{"my-table"}}, nil).
+					Times(1)
+			},
+			statusNotFound,
+			"",
+		},
+		{"wait: invalid account region", []string{"me", "wait", "-account-region", "arn:aws:iam::210987654321:user/exporter@eu-west-1", testExportArn}, nil, statusInvalidArguments, ""},
+		{
+			"wait: filter exports on the table",
+			[]string{"me", "wait", "-s3-bucket", "my-bucket", "-output", "text", "-table-arn", testTableArn},
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{
+					{ExportArn: aws.String(testExportArn), ExportStatus: types.ExportStatusInProgress},
+					{ExportArn: aws.String(testOtherExportArn), ExportStatus: types.ExportStatusInProgress},
+				}).Times(1)
+				seq(
+					describeExportOf(mockClient, testExportArn, &types.ExportDescription{ExportArn: aws.String(testExportArn), ExportStatus: types.ExportStatusInProgress, S3Bucket: aws.String("my-bucket")}).Times(1),
+					describeExportOf(mockClient, testExportArn, waitedExport(startTime)).Times(1),
+				)
+				describeExportOf(mockClient, testOtherExportArn, &types.ExportDescription{ExportArn: aws.String(testOtherExportArn), ExportStatus: types.ExportStatusInProgress, S3Bucket: aws.String("other-bucket")}).Times(1)
+			},
+			statusOK,
+			"",
+		},
+		{"wait: invalid export type filter", []string{"me", "wait", "-type", "PARTIAL", "-table-arn", testTableArn}, nil, statusInvalidArguments, ""},
+		{
+			"wait: export appears later",
+			[]string{"me", "wait", "-min-exports", "1", "-initial-delay", "1ms", "-export-time-after", "2023-10-01T00:00:00Z", "-table-arn", testTableArn},
+			func(mockClient *ddb.MockClient) {
+				seq(
+					listExports(mockClient, nil).Times(1),
+					listExports(mockClient, []types.ExportSummary{{ExportArn: aws.String(testExportArn), ExportStatus: types.ExportStatusCompleted}}).Times(1),
+				)
+				describeExportOf(mockClient, testExportArn, completedExport(startTime)).Times(2)
+			},
+			statusOK,
+			"",
+		},
+		{
+			"wait: export not appeared",
+			[]string{"me", "wait", "-min-exports", "1", "-initial-delay", "1ms", "-max-delay", "1ms", "-appearance-timeout", "10ms", "-started-after", "2023-10-01T00:00:00Z", "-table-arn", testTableArn},
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, nil).MinTimes(1)
+			},
+			statusTimeout,
+			"",
+		},
+		{"wait: min exports without filter", []string{"me", "wait", "-min-exports", "1", "-table-arn", testTableArn}, nil, statusInvalidArguments, ""},
+		{"wait: invalid table regexp", []string{"me", "wait", "-table-regexp", "("}, nil, statusInvalidArguments, ""},
+		{"wait: invalid table tag", []string{"me", "wait", "-table-tag", "=prod"}, nil, statusInvalidArguments, ""},
+		{
+			"wait: timed out while exports wait for the concurrency",
+			[]string{"me", "wait", "-concurrency", "1", "-initial-delay", "1ms", "-max-delay", "1ms", "-timeout", "50ms", testExportArn, testOtherExportArn},
+			func(mockClient *ddb.MockClient) {
+				describeExportOf(mockClient, testExportArn, &types.ExportDescription{ExportArn: aws.String(testExportArn), ExportStatus: types.ExportStatusInProgress}).MinTimes(1)
+				describeExportOf(mockClient, testOtherExportArn, &types.ExportDescription{ExportArn: aws.String(testOtherExportArn), ExportStatus: types.ExportStatusInProgress}).AnyTimes()
+			},
+			statusTimeout,
+			"",
+		},
+		{
+			"wait: quorum reached after a failed export",
+			[]string{"me", "wait", "-quorum", "any", "-concurrency", "1", testExportArn, testOtherExportArn},
+			func(mockClient *ddb.MockClient) {
+				describeExportOf(mockClient, testExportArn, &types.ExportDescription{ExportArn: aws.String(testExportArn), ExportStatus: types.ExportStatusFailed, FailureCode: aws.String("code"), FailureMessage: aws.String("msg")}).Times(1)
+				describeExportOf(mockClient, testOtherExportArn, &types.ExportDescription{ExportArn: aws.String(testOtherExportArn), ExportStatus: types.ExportStatusCompleted}).Times(1)
+			},
+			statusExportFailed,
+			"",
+		},
+		{
+			"wait: quorum any",
+			[]string{"me", "wait", "-quorum", "any", "-concurrency", "1", "-output", "jsonl", testExportArn, testOtherExportArn},
+			func(mockClient *ddb.MockClient) {
+				describeExportOf(mockClient, testExportArn, &types.ExportDescription{ExportArn: aws.String(testExportArn), ExportStatus: types.ExportStatusCompleted}).Times(1)
+			},
+			statusOK,
+			`{"exportArn":"` + testExportArn + `","status":"COMPLETED","s3Bucket":"","s3Prefix":"","s3Url":"","exportManifest":"","itemCount":0,"billedSizeBytes":0,"startTime":null,"endTime":null,"exportTime":null,"polls":1,"waitDuration":"<dynamic>"}` + "\n" +
+				`{"exportArn":"` + testOtherExportArn + `","status":"","s3Bucket":"","s3Prefix":"","s3Url":"","exportManifest":"","itemCount":0,"billedSizeBytes":0,"startTime":null,"endTime":null,"exportTime":null,"polls":0,"waitDuration":"<dynamic>","abandoned":true}` + "\n",
+		},
+		{"wait: unknown backoff", []string{"me", "wait", "-backoff", "fibonacci", testExportArn}, nil, statusInvalidArguments, ""},
+		{
+			"wait: constant backoff",
+			[]string{"me", "wait", "-backoff", "constant", "-initial-delay", "1ms", testExportArn},
+			func(mockClient *ddb.MockClient) {
+				seq(
+					describeExport(mockClient, &types.ExportDescription{ExportArn: aws.String(testExportArn), ExportStatus: types.ExportStatusInProgress}).Times(1),
+					describeExport(mockClient, &types.ExportDescription{ExportArn: aws.String(testExportArn), ExportStatus: types.ExportStatusCompleted}).Times(1),
+				)
+			},
+			statusOK,
+			"",
+		},
+		{"wait: invalid quorum", []string{"me", "wait", "-quorum", "0", testExportArn}, nil, statusInvalidArguments, ""},
+		{
+			"wait: output jsonl even if failed",
+			[]string{"me", "wait", "-export-arn", testExportArn, "-output", "jsonl", "-max-attempts", "1"},
+			func(mockClient *ddb.MockClient) {
+				describeExport(mockClient, &types.ExportDescription{ExportArn: aws.String(testExportArn), ExportStatus: types.ExportStatusFailed}).Times(1)
+			},
+			statusExportFailed,
+			`{"exportArn":"` + testExportArn + `","status":"FAILED","s3Bucket":"","s3Prefix":"","s3Url":"","exportManifest":"","itemCount":0,"billedSizeBytes":0,"startTime":null,"endTime":null,"exportTime":null,"polls":1,"waitDuration":"<dynamic>"}` + "\n",
+		},
+		{
+			"wait: export not found",
+			[]string{"me", "wait", "-export-arn", testExportArn},
+			func(mockClient *ddb.MockClient) {
+				mockClient.EXPECT().DescribeExport(gomock.Any(), gomock.Any()).Return(nil, &smithy.GenericAPIError{Code: "ExportNotFoundException", Fault: smithy.FaultClient}).Times(1)
+			},
+			statusNotFound,
+			"",
+		},
+		{
+			"wait: access denied",
+			[]string{"me", "wait", "-table-arn", testTableArn},
+			func(mockClient *ddb.MockClient) {
+				mockClient.EXPECT().ListExports(gomock.Any(), gomock.Any()).Return(nil, &smithy.GenericAPIError{Code: "AccessDeniedException", Fault: smithy.FaultClient}).Times(1)
+			},
+			statusAccessDenied,
+			"",
+		},
+		{
+			"wait: timed out",
+			[]string{"me", "wait", "-export-arn", testExportArn, "-max-attempts", "1"},
+			func(mockClient *ddb.MockClient) {
+				describeExport(mockClient, &types.ExportDescription{ExportStatus: types.ExportStatusInProgress}).Times(1)
+			},
+			statusTimeout,
+			"",
+		},
+		{
+			"wait: output json",
+			[]string{"me", "wait", "-export-arn", testExportArn, "-output", "json"},
+			func(mockClient *ddb.MockClient) {
+				describeExport(mockClient, waitedExport(startTime)).Times(1)
+			},
+			statusOK,
+			`[
+  {
+    "exportArn": "` + testExportArn + `",
+    "status": "COMPLETED",
+    "s3Bucket": "my-bucket",
+    "s3Prefix": "exports/my-table",
+    "s3Url": "s3://my-bucket/exports/my-table",
+    "exportManifest": "exports/my-table/manifest-summary.json",
+    "itemCount": 42,
+    "billedSizeBytes": 1024,
+    "startTime": "2023-10-01T00:00:00Z",
+    "endTime": "2023-10-01T00:01:00Z",
+    "exportTime": "2023-10-01T00:00:00Z",
+    "polls": 1,
+    "waitDuration": "<dynamic>"
+  }
+]
+`,
+		},
+		{"list: no tableArn specified", []string{"me", "list"}, nil, statusInvalidArguments, ""},
+		{"list: invalid format", []string{"me", "list", "-table-arn", testTableArn, "-format", "xml"}, nil, statusInvalidArguments, ""},
+		{"list: invalid status", []string{"me", "list", "-table-arn", testTableArn, "-status", "DONE"}, nil, statusInvalidArguments, ""},
+		{"list: invalid time range", []string{"me", "list", "-table-arn", testTableArn, "-started-after", "yesterday"}, nil, statusInvalidArguments, ""},
+		{
+			"list",
+			[]string{"me", "list", "-table-arn", testTableArn},
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{{ExportArn: aws.String(testExportArn), ExportStatus: types.ExportStatusCompleted, ExportType: types.ExportTypeFullExport}}).Times(1)
+				describeExportOf(mockClient, testExportArn, completedExport(startTime)).Times(1)
+			},
+			statusOK,
+			fmt.Sprintf("%-*sSTATUS     TYPE         EXPORT TIME           DURATION  ITEMS  SIZE\n", len(testExportArn)+2, "EXPORT ARN") +
+				testExportArn + "  COMPLETED  FULL_EXPORT  2023-10-01T00:00:00Z  1m0s      42     1024\n",
+		},
+		{
+			"list: tsv",
+			[]string{"me", "list", "-table-arn", testTableArn, "-format", "tsv", "-status", "completed", "-type", "FULL_EXPORT"},
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{
+					{ExportArn: aws.String(testExportArn), ExportStatus: types.ExportStatusCompleted, ExportType: types.ExportTypeFullExport},
+					{ExportArn: aws.String(testTableArn + "/export/failed"), ExportStatus: types.ExportStatusFailed, ExportType: types.ExportTypeFullExport},
+				}).Times(1)
+				describeExportOf(mockClient, testExportArn, completedExport(startTime)).Times(1)
+			},
+			statusOK,
+			"EXPORT ARN\tSTATUS\tTYPE\tEXPORT TIME\tDURATION\tITEMS\tSIZE\n" +
+				testExportArn + "\tCOMPLETED\tFULL_EXPORT\t2023-10-01T00:00:00Z\t1m0s\t42\t1024\n",
+		},
+		{
+			"list: csv",
+			[]string{"me", "list", "-table-arn", testTableArn, "-format", "csv", "-started-after", "2023-10-01T00:00:00Z"},
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{{ExportArn: aws.String(testExportArn), ExportStatus: types.ExportStatusCompleted, ExportType: types.ExportTypeFullExport}}).Times(1)
+				describeExportOf(mockClient, testExportArn, completedExport(startTime)).Times(1)
+			},
+			statusOK,
+			"EXPORT ARN,STATUS,TYPE,EXPORT TIME,DURATION,ITEMS,SIZE\n" +
+				testExportArn + ",COMPLETED,FULL_EXPORT,2023-10-01T00:00:00Z,1m0s,42,1024\n",
+		},
+		{
+			"list: json",
+			[]string{"me", "list", "-table-arn", testTableArn, "-format", "json", "-started-before", "2023-10-01T00:00:00Z"},
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{{ExportArn: aws.String(testExportArn), ExportStatus: types.ExportStatusCompleted, ExportType: types.ExportTypeFullExport}}).Times(1)
+				describeExportOf(mockClient, testExportArn, completedExport(startTime)).Times(1)
+			},
+			statusOK,
+			"[]\n",
+		},
+		{"describe: no exportArn specified", []string{"me", "describe"}, nil, statusInvalidArguments, ""},
+		{
+			"describe",
+			[]string{"me", "describe", "-export-arn", testExportArn},
+			func(mockClient *ddb.MockClient) {
+				describeExport(mockClient, &types.ExportDescription{ExportArn: aws.String(testExportArn), ExportStatus: types.ExportStatusFailed, FailureCode: aws.String("code"), FailureMessage: aws.String("msg")}).Times(1)
+			},
+			statusOK,
+			`{
+  "exportArn": "` + testExportArn + `",
+  "status": "FAILED",
+  "s3Bucket": "",
+  "s3Prefix": "",
+  "s3Url": "",
+  "exportManifest": "",
+  "itemCount": 0,
+  "billedSizeBytes": 0,
+  "startTime": null,
+  "endTime": null,
+  "exportTime": null,
+  "polls": 1,
+  "waitDuration": "<dynamic>",
+  "failureCode": "code",
+  "failureMessage": "msg"
+}
+`,
+		},
+		{"start: help", []string{"me", "start", "-help"}, nil, statusOK, ""},
+		{"start: no tableArn specified", []string{"me", "start", "-s3-bucket", "my-bucket"}, nil, statusInvalidArguments, ""},
+		{"start: invalid export format", []string{"me", "start", "-table-arn", testTableArn, "-s3-bucket", "my-bucket", "-export-format", "CSV"}, nil, statusInvalidArguments, ""},
+		{"start: invalid export view type", []string{"me", "start", "-table-arn", testTableArn, "-s3-bucket", "my-bucket", "-incremental", "-export-view-type", "OLD_IMAGE"}, nil, statusInvalidArguments, ""},
+		{"start: invalid export time", []string{"me", "start", "-table-arn", testTableArn, "-s3-bucket", "my-bucket", "-export-time", "yesterday"}, nil, statusInvalidArguments, ""},
+		{
+			"start",
+			[]string{"me", "start", "-table-arn", testTableArn, "-s3-bucket", "my-bucket"},
+			func(mockClient *ddb.MockClient) {
+				mockClient.EXPECT().
+					ExportTableToPointInTime(gomock.Any(), gomock.Any()).
+					Return(&dynamodb.ExportTableToPointInTimeOutput{ExportDescription: &types.ExportDescription{ExportArn: aws.String(testExportArn), ExportStatus: types.ExportStatusInProgress}}, nil).
+					Times(1)
+				describeExport(mockClient, waitedExport(startTime)).Times(1)
+			},
+			statusOK,
+			`{
+  "exportArn": "` + testExportArn + `",
+  "status": "COMPLETED",
+  "s3Bucket": "my-bucket",
+  "s3Prefix": "exports/my-table",
+  "s3Url": "s3://my-bucket/exports/my-table",
+  "exportManifest": "exports/my-table/manifest-summary.json",
+  "itemCount": 42,
+  "billedSizeBytes": 1024,
+  "startTime": "2023-10-01T00:00:00Z",
+  "endTime": "2023-10-01T00:01:00Z",
+  "exportTime": "2023-10-01T00:00:00Z",
+  "polls": 1,
+  "waitDuration": "<dynamic>"
+}
+`,
+		},
+		{
+			"start: export failed",
+			[]string{"me", "start", "-table-arn", testTableArn, "-s3-bucket", "my-bucket"},
+			func(mockClient *ddb.MockClient) {
+				mockClient.EXPECT().
+					ExportTableToPointInTime(gomock.Any(), gomock.Any()).
+					Return(&dynamodb.ExportTableToPointInTimeOutput{ExportDescription: &types.ExportDescription{ExportArn: aws.String(testExportArn), ExportStatus: types.ExportStatusInProgress}}, nil).
+					Times(1)
+				describeExport(mockClient, &types.ExportDescription{ExportArn: aws.String(testExportArn), ExportStatus: types.ExportStatusFailed, FailureCode: aws.String("code"), FailureMessage: aws.String("msg")}).Times(1)
+			},
+			statusExportFailed,
+			`{
+  "exportArn": "` + testExportArn + `",
+  "status": "FAILED",
+  "s3Bucket": "",
+  "s3Prefix": "",
+  "s3Url": "",
+  "exportManifest": "",
+  "itemCount": 0,
+  "billedSizeBytes": 0,
+  "startTime": null,
+  "endTime": null,
+  "exportTime": null,
+  "polls": 1,
+  "waitDuration": "<dynamic>",
+  "failureCode": "code",
+  "failureMessage": "msg"
+}
+`,
+		},
+		{"watch: both tableArn and exportArn specified", []string{"me", "watch", "-table-arn", testTableArn, "-export-arn", testExportArn}, nil, statusInvalidArguments, ""},
+		{
+			"watch",
+			[]string{"me", "watch", "-export-arn", testExportArn, "-initial-delay", "0"},
+			func(mockClient *ddb.MockClient) {
+				seq(
+					describeExport(mockClient, &types.ExportDescription{ExportStatus: types.ExportStatusInProgress}).Times(1),
+					describeExport(mockClient, &types.ExportDescription{ExportStatus: types.ExportStatusCompleted, ItemCount: aws.Int64(3)}).Times(1),
+				)
+			},
+			statusOK,
+			testExportArn + "\tIN_PROGRESS\titems=0\n" +
+				testExportArn + "\tCOMPLETED\titems=3\n" +
+				testExportArn + "\tsettled\n",
+		},
+		{"history: no tableArn specified", []string{"me", "history"}, nil, statusInvalidArguments, ""},
+		{
+			"history",
+			[]string{"me", "history", "-table-arn", testTableArn},
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{
+					{ExportArn: aws.String(testExportArn), ExportStatus: types.ExportStatusCompleted},
+					{ExportArn: aws.String(testTableArn + "/export/in-progress"), ExportStatus: types.ExportStatusInProgress},
+				}).Times(1)
+				describeExportOf(mockClient, testExportArn, &types.ExportDescription{
+					ExportArn:    aws.String(testExportArn),
+					ExportStatus: types.ExportStatusCompleted,
+					ExportType:   types.ExportTypeFullExport,
+					StartTime:    aws.Time(startTime),
+					EndTime:      aws.Time(startTime.Add(time.Minute)),
+				}).Times(1)
+			},
+			statusOK,
+			"START TIME            END TIME              STATUS     TYPE         EXPORT ARN\n" +
+				"2023-10-01T00:00:00Z  2023-10-01T00:01:00Z  COMPLETED  FULL_EXPORT  " + testExportArn + "\n",
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			stream := new(bytes.Buffer)
-			app := NewApp(stream)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := ddb.NewMockClient(ctrl)
+			if tc.onMock != nil {
+				tc.onMock(mockClient)
+			}
+			out := new(bytes.Buffer)
+			errOut := new(bytes.Buffer)
+			app := NewApp(out, errOut)
+			app.pollerOptions = []ddbexportpoller.Option{ddbexportpoller.WithClient(mockClient)}
 			gotStatus := app.Run(tc.argv)
 			if gotStatus != tc.wantStatus {
 				t.Errorf("status:\n\twant=%d\n\tgot=%d", tc.wantStatus, gotStatus)
 			}
-			t.Log(stream.String())
+			if gotOut := maskWaitDuration(out.String()); tc.wantOut != "" && gotOut != tc.wantOut {
+				t.Errorf("output:\n\twant=%q\n\tgot=%q", tc.wantOut, gotOut)
+			}
+			t.Log(errOut.String())
 		})
 	}
 }
+
+var waitDurationPattern = regexp.MustCompile(`("waitDuration": ?)"[^"]+"`)
+
+func maskWaitDuration(s string) string {
+	return waitDurationPattern.ReplaceAllString(s, `$1"<dynamic>"`)
+}
+
+func waitedExport(startTime time.Time) *types.ExportDescription {
+	d := completedExport(startTime)
+	d.S3Bucket = aws.String("my-bucket")
+	d.S3Prefix = aws.String("exports/my-table")
+	d.ExportManifest = aws.String("exports/my-table/manifest-summary.json")
+	return d
+}
+
+func completedExport(startTime time.Time) *types.ExportDescription {
+	return &types.ExportDescription{
+		ExportArn:       aws.String(testExportArn),
+		ExportStatus:    types.ExportStatusCompleted,
+		ExportType:      types.ExportTypeFullExport,
+		ExportTime:      aws.Time(startTime),
+		StartTime:       aws.Time(startTime),
+		EndTime:         aws.Time(startTime.Add(time.Minute)),
+		ItemCount:       aws.Int64(42),
+		BilledSizeBytes: aws.Int64(1024),
+	}
+}
+
+func listExports(mockClient *ddb.MockClient, summaries []types.ExportSummary) *gomock.Call {
+	return mockClient.EXPECT().
+		ListExports(gomock.Any(), gomock.Any()).
+		Return(&dynamodb.ListExportsOutput{ExportSummaries: summaries}, nil)
+}
+
+func describeExport(mockClient *ddb.MockClient, description *types.ExportDescription) *gomock.Call {
+	return mockClient.EXPECT().
+		DescribeExport(gomock.Any(), gomock.Any()).
+		Return(&dynamodb.DescribeExportOutput{ExportDescription: description}, nil)
+}
+
+func describeExportOf(mockClient *ddb.MockClient, exportArn string, description *types.ExportDescription) *gomock.Call {
+	return mockClient.EXPECT().
+		DescribeExport(gomock.Any(), &dynamodb.DescribeExportInput{ExportArn: aws.String(exportArn)}).
+		Return(&dynamodb.DescribeExportOutput{ExportDescription: description}, nil)
+}
+
+func seq(calls ...*gomock.Call) {
+	for i := 1; i < len(calls); i++ {
+		calls[i].After(calls[i-1])
+	}
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+func (c *App) runDescribe(name string, args []string) int {
+	fls := c.newFlagSet(name, name+" -export-arn EXPORT_ARN [flags]")
+	pf := &pollerFlags{}
+	pf.register(fls)
+	var exportArn string
+	fls.StringVar(&exportArn, "export-arn", "", "export ARN to describe")
+	if status, ok := parseFlags(fls, args); !ok {
+		return status
+	}
+	if exportArn == "" {
+		log.Error().Msg("-export-arn is required")
+		return statusInvalidArguments
+	}
+
+	ctx := context.Background()
+	poller, err := pf.newPoller(ctx, c.pollerOptions...)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return errorStatus(err)
+	}
+	ctx, cancel := pf.withTimeout(ctx)
+	defer cancel()
+	description, err := poller.DescribeExport(ctx, exportArn)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return errorStatus(err)
+	}
+	if err := writeJSON(c.out, newDescribedExportSummary(description)); err != nil {
+		log.Error().Err(err).Send()
+		return statusNG
+	}
+	return statusOK
+}
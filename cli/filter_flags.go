@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+
+	ddbexportpoller "github.com/aereal/dynamodb-export-poller"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// exportFilterFlags is a set of flags to build ddbexportpoller.ExportFilter
+type exportFilterFlags struct {
+	statuses      stringsFlag
+	exportTypes   stringsFlag
+	startedAfter  timeFlag
+	startedBefore timeFlag
+	s3Bucket      string
+	s3Prefix      string
+	clientToken   string
+	exportedAfter timeFlag
+}
+
+// register registers the flags except -status. The verb describes what the command does to the selected exports.
+func (f *exportFilterFlags) register(fls *flag.FlagSet, verb string) {
+	fls.Var(&f.exportTypes, "type", fmt.Sprintf("%s only exports with the type; comma-separated or repeatable (%s)", verb, joinValues(types.ExportType("").Values())))
+	fls.Var(&f.startedAfter, "started-after", verb+" only exports started at or after the time in RFC3339 format")
+	fls.Var(&f.startedBefore, "started-before", verb+" only exports started before the time in RFC3339 format")
+	fls.StringVar(&f.s3Bucket, "s3-bucket", "", verb+" only exports to the S3 bucket")
+	fls.StringVar(&f.s3Prefix, "s3-prefix", "", verb+" only exports whose S3 key prefix starts with the prefix")
+	fls.StringVar(&f.clientToken, "client-token", "", verb+" only exports started with the client token")
+	fls.Var(&f.exportedAfter, "export-time-after", verb+" only exports whose point in time to export is at or after the time in RFC3339 format")
+}
+
+func (f *exportFilterFlags) registerStatus(fls *flag.FlagSet, verb string) {
+	fls.Var(&f.statuses, "status", fmt.Sprintf("%s only exports with the status; comma-separated or repeatable (%s)", verb, joinValues(types.ExportStatus("").Values())))
+}
+
+func (f *exportFilterFlags) filter() (ddbexportpoller.ExportFilter, error) {
+	filter := ddbexportpoller.ExportFilter{
+		StartedAfter:    f.startedAfter.Time,
+		StartedBefore:   f.startedBefore.Time,
+		S3Bucket:        f.s3Bucket,
+		S3Prefix:        f.s3Prefix,
+		ClientToken:     f.clientToken,
+		ExportTimeAfter: f.exportedAfter.Time,
+	}
+	for _, s := range f.statuses {
+		status, err := parseExportStatus(s)
+		if err != nil {
+			return filter, err
+		}
+		filter.Statuses = append(filter.Statuses, status)
+	}
+	for _, s := range f.exportTypes {
+		exportType, err := parseExportType(s)
+		if err != nil {
+			return filter, err
+		}
+		filter.ExportTypes = append(filter.ExportTypes, exportType)
+	}
+	return filter, nil
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	ddbexportpoller "github.com/aereal/dynamodb-export-poller"
+)
+
+// stringsFlag is a flag.Value that accepts comma-separated values and can be specified multiple times
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(v string) error {
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			*f = append(*f, s)
+		}
+	}
+	return nil
+}
+
+// timeFlag is a flag.Value that accepts a time in RFC3339 format
+type timeFlag struct {
+	time.Time
+}
+
+func (f *timeFlag) String() string {
+	if f.IsZero() {
+		return ""
+	}
+	return f.Format(time.RFC3339)
+}
+
+func (f *timeFlag) Set(v string) error {
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return err
+	}
+	f.Time = t
+	return nil
+}
+
+// quorumFlag is a flag.Value that accepts all, any or the number of exports that must complete
+type quorumFlag struct {
+	n int
+}
+
+func (f *quorumFlag) String() string {
+	switch f.n {
+	case ddbexportpoller.QuorumAll:
+		return "all"
+	case ddbexportpoller.QuorumAny:
+		return "any"
+	default:
+		return strconv.Itoa(f.n)
+	}
+}
+
+func (f *quorumFlag) Set(v string) error {
+	switch v {
+	case "all":
+		f.n = ddbexportpoller.QuorumAll
+		return nil
+	case "any":
+		f.n = ddbexportpoller.QuorumAny
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n <= 0 {
+		return fmt.Errorf("quorum must be all, any or a positive number but got %q", v)
+	}
+	f.n = n
+	return nil
+}
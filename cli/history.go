@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+
+	ddbexportpoller "github.com/aereal/dynamodb-export-poller"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/rs/zerolog/log"
+)
+
+func (c *App) runHistory(name string, args []string) int {
+	fls := c.newFlagSet(name, name+" (-table-arn TABLE_ARN | -table TABLE_NAME) [flags]")
+	pf := &pollerFlags{}
+	pf.register(fls)
+	var tf tableFlags
+	tf.register(fls, "table ARN to show export history")
+	if status, ok := parseFlags(fls, args); !ok {
+		return status
+	}
+	if err := tf.validate(true); err != nil {
+		log.Error().Err(err).Send()
+		return statusInvalidArguments
+	}
+
+	ctx := context.Background()
+	poller, err := pf.newPoller(ctx, c.pollerOptions...)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return errorStatus(err)
+	}
+	ctx, cancel := pf.withTimeout(ctx)
+	defer cancel()
+	tableArn, err := tf.resolve(ctx, poller)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return errorStatus(err)
+	}
+	filter := ddbexportpoller.ExportFilter{Statuses: []types.ExportStatus{types.ExportStatusCompleted, types.ExportStatusFailed}}
+	finished, err := poller.DescribeExportsOnTable(ctx, tableArn, filter)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return errorStatus(err)
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		return aws.ToTime(finished[i].StartTime).Before(aws.ToTime(finished[j].StartTime))
+	})
+	tw := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "START TIME\tEND TIME\tSTATUS\tTYPE\tEXPORT ARN")
+	for _, d := range finished {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", formatTime(d.StartTime), formatTime(d.EndTime), d.ExportStatus, d.ExportType, aws.ToString(d.ExportArn))
+	}
+	if err := tw.Flush(); err != nil {
+		log.Error().Err(err).Send()
+		return statusNG
+	}
+	return statusOK
+}
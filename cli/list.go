@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/rs/zerolog/log"
+)
+
+func (c *App) runList(name string, args []string) int {
+	fls := c.newFlagSet(name, name+" (-table-arn TABLE_ARN | -table TABLE_NAME) [flags]")
+	pf := &pollerFlags{}
+	pf.register(fls)
+	var (
+		tf     tableFlags
+		format string
+		ff     exportFilterFlags
+	)
+	tf.register(fls, "table ARN to list exports")
+	fls.StringVar(&format, "format", formatTable, fmt.Sprintf("output format (%s)", strings.Join(exportFormats, ", ")))
+	ff.registerStatus(fls, "list")
+	ff.register(fls, "list")
+	if status, ok := parseFlags(fls, args); !ok {
+		return status
+	}
+	if err := tf.validate(true); err != nil {
+		log.Error().Err(err).Send()
+		return statusInvalidArguments
+	}
+	if err := validateFormat(format, exportFormats); err != nil {
+		log.Error().Err(err).Send()
+		return statusInvalidArguments
+	}
+	filter, err := ff.filter()
+	if err != nil {
+		log.Error().Err(err).Send()
+		return statusInvalidArguments
+	}
+
+	ctx := context.Background()
+	poller, err := pf.newPoller(ctx, c.pollerOptions...)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return errorStatus(err)
+	}
+	ctx, cancel := pf.withTimeout(ctx)
+	defer cancel()
+	tableArn, err := tf.resolve(ctx, poller)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return errorStatus(err)
+	}
+	descriptions, err := poller.DescribeExportsOnTable(ctx, tableArn, filter)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return errorStatus(err)
+	}
+	rows := make([]exportRow, len(descriptions))
+	for i, d := range descriptions {
+		rows[i] = newExportRow(d)
+	}
+	if err := writeExportRows(c.out, format, rows); err != nil {
+		log.Error().Err(err).Send()
+		return statusNG
+	}
+	return statusOK
+}
+
+func parseExportStatus(s string) (types.ExportStatus, error) {
+	for _, v := range types.ExportStatus("").Values() {
+		if strings.EqualFold(s, string(v)) {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("unknown export status: %q", s)
+}
+
+func parseExportType(s string) (types.ExportType, error) {
+	for _, v := range types.ExportType("").Values() {
+		if strings.EqualFold(s, string(v)) {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("unknown export type: %q", s)
+}
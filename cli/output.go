@@ -0,0 +1,275 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	ddbexportpoller "github.com/aereal/dynamodb-export-poller"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatTSV   = "tsv"
+	formatCSV   = "csv"
+)
+
+var exportFormats = []string{formatTable, formatJSON, formatTSV, formatCSV}
+
+func validateFormat(format string, formats []string) error {
+	for _, f := range formats {
+		if f == format {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown format: %q (available: %s)", format, strings.Join(formats, ", "))
+}
+
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// exportRow is a row of export list
+type exportRow struct {
+	ExportArn       string             `json:"exportArn"`
+	Status          types.ExportStatus `json:"status"`
+	Type            types.ExportType   `json:"type"`
+	ExportTime      *time.Time         `json:"exportTime"`
+	Duration        string             `json:"duration"`
+	ItemCount       int64              `json:"itemCount"`
+	BilledSizeBytes int64              `json:"billedSizeBytes"`
+}
+
+var exportRowHeader = []string{"EXPORT ARN", "STATUS", "TYPE", "EXPORT TIME", "DURATION", "ITEMS", "SIZE"}
+
+func newExportRow(d *types.ExportDescription) exportRow {
+	row := exportRow{
+		ExportArn:       aws.ToString(d.ExportArn),
+		Status:          d.ExportStatus,
+		Type:            d.ExportType,
+		ExportTime:      d.ExportTime,
+		ItemCount:       aws.ToInt64(d.ItemCount),
+		BilledSizeBytes: aws.ToInt64(d.BilledSizeBytes),
+	}
+	if row.Type == "" {
+		row.Type = types.ExportTypeFullExport
+	}
+	if d.StartTime != nil && d.EndTime != nil {
+		row.Duration = d.EndTime.Sub(*d.StartTime).String()
+	}
+	return row
+}
+
+func (r exportRow) fields() []string {
+	return []string{
+		r.ExportArn,
+		string(r.Status),
+		string(r.Type),
+		formatTime(r.ExportTime),
+		orDash(r.Duration),
+		strconv.FormatInt(r.ItemCount, 10),
+		strconv.FormatInt(r.BilledSizeBytes, 10),
+	}
+}
+
+func writeExportRows(w io.Writer, format string, rows []exportRow) error {
+	switch format {
+	case formatJSON:
+		if rows == nil {
+			rows = []exportRow{}
+		}
+		return writeJSON(w, rows)
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(exportRowHeader); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if err := cw.Write(row.fields()); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	case formatTSV:
+		fmt.Fprintln(w, strings.Join(exportRowHeader, "\t"))
+		for _, row := range rows {
+			fmt.Fprintln(w, strings.Join(row.fields(), "\t"))
+		}
+		return nil
+	default:
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(exportRowHeader, "\t"))
+		for _, row := range rows {
+			fmt.Fprintln(tw, strings.Join(row.fields(), "\t"))
+		}
+		return tw.Flush()
+	}
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.Format(time.RFC3339)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+const (
+	outputJSON       = "json"
+	outputJSONL      = "jsonl"
+	outputText       = "text"
+	outputGoTemplate = "go-template"
+)
+
+// exportSummary is a structured summary of the waited export
+type exportSummary struct {
+	ExportArn       string             `json:"exportArn"`
+	Status          types.ExportStatus `json:"status"`
+	S3Bucket        string             `json:"s3Bucket"`
+	S3Prefix        string             `json:"s3Prefix"`
+	S3URL           string             `json:"s3Url"`
+	ExportManifest  string             `json:"exportManifest"`
+	ItemCount       int64              `json:"itemCount"`
+	BilledSizeBytes int64              `json:"billedSizeBytes"`
+	StartTime       *time.Time         `json:"startTime"`
+	EndTime         *time.Time         `json:"endTime"`
+	ExportTime      *time.Time         `json:"exportTime"`
+	Polls           int                `json:"polls"`
+	WaitDuration    string             `json:"waitDuration"`
+	Abandoned       bool               `json:"abandoned,omitempty"`
+	FailureCode     string             `json:"failureCode,omitempty"`
+	FailureMessage  string             `json:"failureMessage,omitempty"`
+}
+
+func newExportSummary(r *ddbexportpoller.ExportResult) exportSummary {
+	s := exportSummary{
+		ExportArn:       r.ExportArn,
+		Status:          r.Status,
+		S3Bucket:        r.S3Bucket,
+		S3Prefix:        r.S3Prefix,
+		ExportManifest:  r.ExportManifest,
+		ItemCount:       r.ItemCount,
+		BilledSizeBytes: r.BilledSizeBytes,
+		StartTime:       timeOrNil(r.StartTime),
+		EndTime:         timeOrNil(r.EndTime),
+		ExportTime:      timeOrNil(r.ExportTime),
+		Polls:           r.Polls,
+		WaitDuration:    r.WaitDuration.String(),
+		Abandoned:       r.Abandoned,
+	}
+	if d := r.Description; d != nil {
+		s.FailureCode = aws.ToString(d.FailureCode)
+		s.FailureMessage = aws.ToString(d.FailureMessage)
+	}
+	if r.S3Bucket != "" {
+		s.S3URL = (&url.URL{Scheme: "s3", Host: r.S3Bucket, Path: "/" + r.S3Prefix}).String()
+	}
+	return s
+}
+
+// newDescribedExportSummary returns the summary of the export described by a single request.
+func newDescribedExportSummary(d *types.ExportDescription) exportSummary {
+	return newExportSummary(&ddbexportpoller.ExportResult{
+		ExportArn:       aws.ToString(d.ExportArn),
+		Status:          d.ExportStatus,
+		StartTime:       aws.ToTime(d.StartTime),
+		EndTime:         aws.ToTime(d.EndTime),
+		ExportTime:      aws.ToTime(d.ExportTime),
+		S3Bucket:        aws.ToString(d.S3Bucket),
+		S3Prefix:        aws.ToString(d.S3Prefix),
+		ExportManifest:  aws.ToString(d.ExportManifest),
+		ItemCount:       aws.ToInt64(d.ItemCount),
+		BilledSizeBytes: aws.ToInt64(d.BilledSizeBytes),
+		Polls:           1,
+		Description:     d,
+	})
+}
+
+func timeOrNil(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// resultWriter writes summaries of waited exports in the format given by -output flag
+type resultWriter struct {
+	format string
+	tmpl   *template.Template
+}
+
+func newResultWriter(output string) (*resultWriter, error) {
+	if output == "" {
+		return nil, nil
+	}
+	if strings.HasPrefix(output, outputGoTemplate+"=") {
+		tmpl, err := template.New("output").Parse(strings.TrimPrefix(output, outputGoTemplate+"="))
+		if err != nil {
+			return nil, fmt.Errorf("invalid template: %w", err)
+		}
+		return &resultWriter{format: outputGoTemplate, tmpl: tmpl}, nil
+	}
+	switch output {
+	case outputJSON, outputJSONL, outputText:
+		return &resultWriter{format: output}, nil
+	default:
+		return nil, fmt.Errorf("unknown output: %q (available: %s, %s, %s, %s=TEMPLATE)", output, outputJSON, outputJSONL, outputText, outputGoTemplate)
+	}
+}
+
+func (rw *resultWriter) write(w io.Writer, results []*ddbexportpoller.ExportResult) error {
+	if rw == nil {
+		return nil
+	}
+	summaries := make([]exportSummary, 0, len(results))
+	for _, r := range results {
+		if r == nil {
+			continue
+		}
+		summaries = append(summaries, newExportSummary(r))
+	}
+	switch rw.format {
+	case outputJSON:
+		return writeJSON(w, summaries)
+	case outputJSONL:
+		enc := json.NewEncoder(w)
+		for _, s := range summaries {
+			if err := enc.Encode(s); err != nil {
+				return err
+			}
+		}
+		return nil
+	case outputGoTemplate:
+		for _, s := range summaries {
+			if err := rw.tmpl.Execute(w, s); err != nil {
+				return err
+			}
+			fmt.Fprintln(w)
+		}
+		return nil
+	default:
+		for _, s := range summaries {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n", s.ExportArn, s.Status, orDash(s.S3URL), orDash(s.ExportManifest), s.ItemCount)
+		}
+		return nil
+	}
+}
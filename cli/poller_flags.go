@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"runtime"
+	"strings"
+	"time"
+
+	ddbexportpoller "github.com/aereal/dynamodb-export-poller"
+	"github.com/rs/zerolog"
+)
+
+// pollerFlags is a set of flags shared by commands that build the Poller
+type pollerFlags struct {
+	debug               bool
+	opts                ddbexportpoller.PollerOptions
+	listExportsPageSize int
+	accountRegions      stringsFlag
+	backoff             string
+	aws                 awsConfigFlags
+}
+
+func (f *pollerFlags) register(fls *flag.FlagSet) {
+	f.aws.register(fls)
+	fls.BoolVar(&f.debug, "debug", false, "enable debug logging")
+	fls.DurationVar(&f.opts.InitialDelay, "initial-delay", time.Second, "initial wait time")
+	fls.DurationVar(&f.opts.MaxDelay, "max-delay", time.Second*10, "max wait time")
+	fls.StringVar(&f.backoff, "backoff", ddbexportpoller.BackoffExponential, "backoff strategy between status checks from -initial-delay up to -max-delay (constant, linear, exponential, full-jitter, decorrelated-jitter)")
+	fls.BoolVar(&f.opts.AdaptiveScheduling, "adaptive", false, "schedule status checks by the completion time predicted from the table size and past export durations")
+	fls.DurationVar(&f.opts.AdaptiveMaxDelay, "adaptive-max-delay", 0, "max wait time of -adaptive (zero means 10m)")
+	fls.Int64Var(&f.opts.Concurrency, "concurrency", int64(runtime.NumCPU()), "concurrency to run requests")
+	fls.IntVar(&f.opts.MaxAttempts, "max-attempts", 0, "max attempts (zero means forever)")
+	fls.DurationVar(&f.opts.Timeout, "timeout", 0, "global timeout of the whole command (zero means waits forever)")
+	fls.DurationVar(&f.opts.ExportTimeout, "export-timeout", 0, "time limit to wait for each export (zero means waits forever)")
+	fls.DurationVar(&f.opts.RequestTimeout, "request-timeout", 0, "time limit of each API request; DescribeExport and ListExports requests are retried on timeout (zero means no limit)")
+	fls.IntVar(&f.listExportsPageSize, "list-exports-page-size", 0, "max exports per ListExports request (zero means the service default)")
+	fls.IntVar(&f.opts.MaxListExportsPages, "max-list-exports-pages", 0, "max ListExports pages to scan (zero means all pages)")
+	fls.Float64Var(&f.opts.RequestRate, "request-rate", 0, "max DescribeExport and ListExports requests per second, lowered while throttled (zero means unlimited)")
+	fls.IntVar(&f.opts.RequestBurst, "request-burst", 0, "max requests sent at once within -request-rate (zero means 1)")
+	fls.Var(&f.accountRegions, "account-region", "[ROLE_ARN@]REGION to access tables and exports in another account or region by assuming the role; comma-separated or repeatable")
+}
+
+// parseAccountRegion parses [ROLE_ARN@]REGION.
+func parseAccountRegion(s string) ddbexportpoller.AccountRegion {
+	i := strings.LastIndex(s, "@")
+	if i < 0 {
+		return ddbexportpoller.AccountRegion{Region: s}
+	}
+	return ddbexportpoller.AccountRegion{RoleArn: s[:i], Region: s[i+1:]}
+}
+
+// withTimeout returns the context done at -timeout, so that the timeout limits the whole command including resolving the tables.
+func (f *pollerFlags) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if f.opts.Timeout == 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, f.opts.Timeout)
+}
+
+func (f *pollerFlags) newPoller(ctx context.Context, opts ...ddbexportpoller.Option) (*ddbexportpoller.Poller, error) {
+	if f.debug {
+		zerolog.SetGlobalLevel(zerolog.DebugLevel)
+	}
+	f.opts.ListExportsPageSize = int32(f.listExportsPageSize)
+	backoff, err := ddbexportpoller.NewBackoffStrategy(f.backoff, f.opts.InitialDelay, f.opts.MaxDelay)
+	if err != nil {
+		return nil, err
+	}
+	f.opts.Backoff = backoff
+	for _, s := range f.accountRegions {
+		f.opts.AccountRegions = append(f.opts.AccountRegions, parseAccountRegion(s))
+	}
+	cfg, err := f.aws.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return ddbexportpoller.NewPoller(f.opts, append([]ddbexportpoller.Option{ddbexportpoller.WithAWSConfig(cfg)}, opts...)...)
+}
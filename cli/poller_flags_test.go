@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"reflect"
+	"testing"
+
+	ddbexportpoller "github.com/aereal/dynamodb-export-poller"
+)
+
+func TestParseAccountRegion(t *testing.T) {
+	testCases := []struct {
+		name string
+		s    string
+		want ddbexportpoller.AccountRegion
+	}{
+		{"region only", "eu-west-1", ddbexportpoller.AccountRegion{Region: "eu-west-1"}},
+		{"role and region", "arn:aws:iam::210987654321:role/exporter@eu-west-1", ddbexportpoller.AccountRegion{RoleArn: "arn:aws:iam::210987654321:role/exporter", Region: "eu-west-1"}},
+		{"role name with @", "arn:aws:iam::210987654321:role/exporter@example.com@eu-west-1", ddbexportpoller.AccountRegion{RoleArn: "arn:aws:iam::210987654321:role/exporter@example.com", Region: "eu-west-1"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := parseAccountRegion(tc.s); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("account region:\n\twant=%#v\n\tgot=%#v", tc.want, got)
+			}
+		})
+	}
+}
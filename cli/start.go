@@ -0,0 +1,143 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	ddbexportpoller "github.com/aereal/dynamodb-export-poller"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/rs/zerolog/log"
+)
+
+func (c *App) runStart(name string, args []string) int {
+	fls := c.newFlagSet(name, name+" (-table-arn TABLE_ARN | -table TABLE_NAME) -s3-bucket BUCKET [flags]")
+	pf := &pollerFlags{}
+	pf.register(fls)
+	var (
+		req          ddbexportpoller.ExportRequest
+		tf           tableFlags
+		sseAlgorithm string
+		exportFormat string
+		exportTime   string
+		incremental  bool
+		viewType     string
+		exportToTime string
+	)
+	tf.register(fls, "table ARN to export")
+	fls.StringVar(&req.S3Bucket, "s3-bucket", "", "S3 bucket name to export to")
+	fls.StringVar(&req.S3Prefix, "s3-prefix", "", "S3 key prefix of the exported data")
+	fls.StringVar(&req.S3BucketOwner, "s3-bucket-owner", "", "AWS account ID that owns the bucket")
+	fls.StringVar(&sseAlgorithm, "s3-sse-algorithm", "", fmt.Sprintf("encryption type of the exported data (%s)", joinValues(types.S3SseAlgorithm("").Values())))
+	fls.StringVar(&req.S3SseKmsKeyId, "s3-sse-kms-key-id", "", "KMS key ID to encrypt the exported data")
+	fls.StringVar(&exportFormat, "export-format", "", fmt.Sprintf("format of the exported data (%s)", joinValues(types.ExportFormat("").Values())))
+	fls.StringVar(&exportTime, "export-time", "", "point in time to export in RFC3339 format (default: now)")
+	fls.StringVar(&req.ClientToken, "client-token", "", "token to make the export request idempotent")
+	fls.BoolVar(&incremental, "incremental", false, "start an incremental export continuing from the last completed export")
+	fls.StringVar(&viewType, "export-view-type", "", fmt.Sprintf("view of the incrementally exported items (%s)", joinValues(types.ExportViewType("").Values())))
+	fls.StringVar(&exportToTime, "export-to-time", "", "end of the incremental export window in RFC3339 format (default: now)")
+	if status, ok := parseFlags(fls, args); !ok {
+		return status
+	}
+
+	if err := tf.validate(false); err != nil {
+		log.Error().Err(err).Send()
+		return statusInvalidArguments
+	}
+	var err error
+	if req.S3SseAlgorithm, err = parseS3SseAlgorithm(sseAlgorithm); err != nil {
+		log.Error().Err(err).Send()
+		return statusInvalidArguments
+	}
+	if req.ExportFormat, err = parseExportFormat(exportFormat); err != nil {
+		log.Error().Err(err).Send()
+		return statusInvalidArguments
+	}
+	if exportTime != "" {
+		if req.ExportTime, err = time.Parse(time.RFC3339, exportTime); err != nil {
+			log.Error().Err(err).Msg("invalid -export-time")
+			return statusInvalidArguments
+		}
+	}
+	if req.ExportViewType, err = parseExportViewType(viewType); err != nil {
+		log.Error().Err(err).Send()
+		return statusInvalidArguments
+	}
+	if exportToTime != "" {
+		if req.ExportToTime, err = time.Parse(time.RFC3339, exportToTime); err != nil {
+			log.Error().Err(err).Msg("invalid -export-to-time")
+			return statusInvalidArguments
+		}
+	}
+
+	ctx := context.Background()
+	poller, err := pf.newPoller(ctx, c.pollerOptions...)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return errorStatus(err)
+	}
+	ctx, cancel := pf.withTimeout(ctx)
+	defer cancel()
+	if req.TableArn, err = tf.resolve(ctx, poller); err != nil {
+		log.Error().Err(err).Send()
+		return errorStatus(err)
+	}
+	exportAndWait := poller.ExportAndWait
+	if incremental {
+		exportAndWait = poller.IncrementalExportAndWait
+	}
+	result, err := exportAndWait(ctx, req)
+	// the result is written even if waiting failed as long as the export started
+	if result != nil {
+		if writeErr := writeJSON(c.out, newExportSummary(result)); writeErr != nil {
+			log.Error().Err(writeErr).Send()
+			return statusNG
+		}
+	}
+	if err != nil {
+		log.Error().Err(err).Send()
+		return errorStatus(err)
+	}
+	return statusOK
+}
+
+func parseS3SseAlgorithm(s string) (types.S3SseAlgorithm, error) {
+	for _, v := range types.S3SseAlgorithm("").Values() {
+		if strings.EqualFold(s, string(v)) {
+			return v, nil
+		}
+	}
+	if s == "" {
+		return "", nil
+	}
+	return "", fmt.Errorf("unknown S3 SSE algorithm: %q", s)
+}
+
+func parseExportFormat(s string) (types.ExportFormat, error) {
+	for _, v := range types.ExportFormat("").Values() {
+		if strings.EqualFold(s, string(v)) {
+			return v, nil
+		}
+	}
+	if s == "" {
+		return "", nil
+	}
+	return "", fmt.Errorf("unknown export format: %q", s)
+}
+
+func parseExportViewType(s string) (types.ExportViewType, error) {
+	for _, v := range types.ExportViewType("").Values() {
+		if strings.EqualFold(s, string(v)) {
+			return v, nil
+		}
+	}
+	if s == "" {
+		return "", nil
+	}
+	return "", fmt.Errorf("unknown export view type: %q", s)
+}
+
+func joinValues(values interface{}) string {
+	return strings.Trim(fmt.Sprint(values), "[]")
+}
@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestParseExportFormat(t *testing.T) {
+	testCases := []struct {
+		input   string
+		want    types.ExportFormat
+		wantErr bool
+	}{
+		{"", "", false},
+		{"ION", types.ExportFormatIon, false},
+		{"dynamodb_json", types.ExportFormatDynamodbJson, false},
+		{"CSV", "", true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := parseExportFormat(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("error:\n\twant=%v\n\tgot=%v", tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Errorf("format:\n\twant=%s\n\tgot=%s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestParseS3SseAlgorithm(t *testing.T) {
+	testCases := []struct {
+		input   string
+		want    types.S3SseAlgorithm
+		wantErr bool
+	}{
+		{"", "", false},
+		{"AES256", types.S3SseAlgorithmAes256, false},
+		{"kms", types.S3SseAlgorithmKms, false},
+		{"DES", "", true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			got, err := parseS3SseAlgorithm(tc.input)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("error:\n\twant=%v\n\tgot=%v", tc.wantErr, err)
+			}
+			if got != tc.want {
+				t.Errorf("algorithm:\n\twant=%s\n\tgot=%s", tc.want, got)
+			}
+		})
+	}
+}
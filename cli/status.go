@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"path"
+
+	ddbexportpoller "github.com/aereal/dynamodb-export-poller"
+	"github.com/aws/smithy-go"
+	"github.com/hashicorp/go-multierror"
+)
+
+// Exit statuses of the commands. The values are part of the CLI interface, so do not reorder them.
+const (
+	// statusOK means the command succeeded
+	statusOK int = iota
+
+	// statusNG means the command failed for a reason not categorized below
+	statusNG
+
+	// statusExportFailed means the export job finished with FAILED status
+	statusExportFailed
+
+	// statusTimeout means the export job did not finish within the timeout or max attempts
+	statusTimeout
+
+	// statusNotFound means the table or the export job does not exist
+	statusNotFound
+
+	// statusAccessDenied means the credentials are invalid or lack permissions
+	statusAccessDenied
+
+	// statusInvalidArguments means the command is called with invalid flags or arguments
+	statusInvalidArguments
+)
+
+var invalidArgumentErrors = []error{
+	ddbexportpoller.ErrTableArnRequired,
+	ddbexportpoller.ErrExportArnRequired,
+	ddbexportpoller.ErrS3BucketRequired,
+	ddbexportpoller.ErrIncrementalExportWindowRequired,
+	ddbexportpoller.ErrTargetsRequired,
+	ddbexportpoller.ErrAccountRegionRegionRequired,
+	ddbexportpoller.ErrConcurrencyMustBePositive,
+	ddbexportpoller.ErrListExportsPageSizeOutOfRange,
+	ddbexportpoller.ErrMaxListExportsPagesMustNotBeNegative,
+	ddbexportpoller.ErrMinExportsMustNotBeNegative,
+	ddbexportpoller.ErrMinExportsRequiresNewExportsFilter,
+	ddbexportpoller.ErrQuorumMustNotBeNegative,
+	ddbexportpoller.ErrRequestRateMustNotBeNegative,
+	ddbexportpoller.ErrRequestBurstMustNotBeNegative,
+	ddbexportpoller.ErrUnknownBackoffStrategy,
+	errRoleArnRequired,
+	path.ErrBadPattern,
+}
+
+var notFoundErrors = []error{
+	ddbexportpoller.ErrNoTablesMatched,
+}
+
+var apiErrorStatuses = map[string]int{
+	"ValidationException":                     statusInvalidArguments,
+	"AccessDeniedException":                   statusAccessDenied,
+	"AccessDenied":                            statusAccessDenied,
+	"UnrecognizedClientException":             statusAccessDenied,
+	"InvalidClientTokenId":                    statusAccessDenied,
+	"ExpiredTokenException":                   statusAccessDenied,
+	"ExpiredToken":                            statusAccessDenied,
+	"MissingAuthenticationToken":              statusAccessDenied,
+	"InvalidSignatureException":               statusAccessDenied,
+	"ResourceNotFoundException":               statusNotFound,
+	"TableNotFoundException":                  statusNotFound,
+	"ExportNotFoundException":                 statusNotFound,
+	"PointInTimeRecoveryUnavailableException": statusInvalidArguments,
+}
+
+// statusPrecedence lists the statuses in the order to choose if the error consists of several errors.
+var statusPrecedence = []int{statusInvalidArguments, statusAccessDenied, statusNotFound, statusExportFailed, statusTimeout, statusNG}
+
+// errorStatus maps the error to the exit status.
+//
+// If err consists of several errors, the status earlier in statusPrecedence wins.
+func errorStatus(err error) int {
+	var merr *multierror.Error
+	if !errors.As(err, &merr) {
+		return singleErrorStatus(err)
+	}
+	found := map[int]bool{}
+	for _, e := range merr.WrappedErrors() {
+		found[errorStatus(e)] = true
+	}
+	for _, status := range statusPrecedence {
+		if found[status] {
+			return status
+		}
+	}
+	return statusNG
+}
+
+func singleErrorStatus(err error) int {
+	if err == nil {
+		return statusOK
+	}
+	for _, target := range invalidArgumentErrors {
+		if errors.Is(err, target) {
+			return statusInvalidArguments
+		}
+	}
+	for _, target := range notFoundErrors {
+		if errors.Is(err, target) {
+			return statusNotFound
+		}
+	}
+	var arnErr *ddbexportpoller.InvalidARNError
+	if errors.As(err, &arnErr) {
+		return statusInvalidArguments
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		if status, ok := apiErrorStatuses[apiErr.ErrorCode()]; ok {
+			return status
+		}
+	}
+	var failedErr *ddbexportpoller.ExportFailedError
+	if errors.As(err, &failedErr) {
+		return statusExportFailed
+	}
+	var notAppearedErr *ddbexportpoller.ExportsNotAppearedError
+	if errors.As(err, &notAppearedErr) {
+		return statusTimeout
+	}
+	if errors.Is(err, ddbexportpoller.ErrExportHasNotBeenFinished) || errors.Is(err, context.DeadlineExceeded) {
+		return statusTimeout
+	}
+	return statusNG
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"testing"
+	"time"
+
+	ddbexportpoller "github.com/aereal/dynamodb-export-poller"
+	"github.com/aws/smithy-go"
+	"github.com/hashicorp/go-multierror"
+)
+
+func TestErrorStatus(t *testing.T) {
+	apiError := func(code string) error {
+		return &smithy.OperationError{
+			ServiceID:     "DynamoDB",
+			OperationName: "DescribeExport",
+			Err:           &smithy.GenericAPIError{Code: code},
+		}
+	}
+	testCases := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"no error", nil, statusOK},
+		{"other error", errors.New("oops"), statusNG},
+		{"unknown API error", apiError("InternalServerError"), statusNG},
+		{"export failed", &ddbexportpoller.ExportFailedError{ExportArn: testExportArn}, statusExportFailed},
+		{"wrapped export failed", multierror.Append(nil, &ddbexportpoller.ExportFailedError{}), statusExportFailed},
+		{"export has not been finished", ddbexportpoller.ErrExportHasNotBeenFinished, statusTimeout},
+		{"exports not appeared", &ddbexportpoller.ExportsNotAppearedError{TableArn: testTableArn, Want: 1}, statusTimeout},
+		{"deadline exceeded", fmt.Errorf("DescribeExport(): %w", context.DeadlineExceeded), statusTimeout},
+		{"exports timed out", &ddbexportpoller.TimeoutError{Timeout: time.Minute, Exports: []ddbexportpoller.TimedOutExport{{ExportArn: testExportArn}}}, statusTimeout},
+		{"export not found", apiError("ExportNotFoundException"), statusNotFound},
+		{"table not found", apiError("TableNotFoundException"), statusNotFound},
+		{"resource not found", apiError("ResourceNotFoundException"), statusNotFound},
+		{"access denied", apiError("AccessDeniedException"), statusAccessDenied},
+		{"unrecognized client", apiError("UnrecognizedClientException"), statusAccessDenied},
+		{"expired token", apiError("ExpiredTokenException"), statusAccessDenied},
+		{"validation error", apiError("ValidationException"), statusInvalidArguments},
+		{"table ARN required", ddbexportpoller.ErrTableArnRequired, statusInvalidArguments},
+		{"export ARN required", ddbexportpoller.ErrExportArnRequired, statusInvalidArguments},
+		{"S3 bucket required", ddbexportpoller.ErrS3BucketRequired, statusInvalidArguments},
+		{"invalid poller options", multierror.Append(nil, ddbexportpoller.ErrConcurrencyMustBePositive), statusInvalidArguments},
+		{"role ARN required", errRoleArnRequired, statusInvalidArguments},
+		{"invalid role ARN", &ddbexportpoller.InvalidARNError{ARN: "exporter", Resource: "role"}, statusInvalidArguments},
+		{"account region without region", multierror.Append(nil, ddbexportpoller.ErrAccountRegionRegionRequired), statusInvalidArguments},
+		{"bad table name pattern", fmt.Errorf("invalid table name pattern: %w", path.ErrBadPattern), statusInvalidArguments},
+		{"no tables matched", ddbexportpoller.ErrNoTablesMatched, statusNotFound},
+		{"export failed wins over timeout", multierror.Append(nil, ddbexportpoller.ErrExportHasNotBeenFinished, &ddbexportpoller.ExportFailedError{}), statusExportFailed},
+		{"access denied wins over export failed", multierror.Append(nil, &ddbexportpoller.ExportFailedError{}, apiError("AccessDeniedException")), statusAccessDenied},
+		{"access denied wins over not found", multierror.Append(nil, apiError("ExportNotFoundException"), apiError("AccessDeniedException")), statusAccessDenied},
+		{"empty multierror", &multierror.Error{}, statusNG},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := errorStatus(tc.err); got != tc.want {
+				t.Errorf("status:\n\twant=%d\n\tgot=%d", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+
+	ddbexportpoller "github.com/aereal/dynamodb-export-poller"
+)
+
+var (
+	errTableRequired = errors.New("-table-arn or -table is required")
+	errTableConflict = errors.New("either of one of -table-arn or -table must be specified")
+)
+
+// tableFlags is a pair of flags to specify the table by either of its ARN or name
+type tableFlags struct {
+	arn  string
+	name string
+}
+
+func (f *tableFlags) register(fls *flag.FlagSet, usage string) {
+	fls.StringVar(&f.arn, "table-arn", "", usage)
+	fls.StringVar(&f.name, "table", "", "table name resolved to the ARN in the configured region, instead of -table-arn")
+}
+
+func (f *tableFlags) present() bool {
+	return f.arn != "" || f.name != ""
+}
+
+// validate returns an error if both of the flags are given, or neither is given though required.
+func (f *tableFlags) validate(required bool) error {
+	switch {
+	case f.arn != "" && f.name != "":
+		return errTableConflict
+	case required && !f.present():
+		return errTableRequired
+	}
+	return nil
+}
+
+func (f *tableFlags) resolve(ctx context.Context, poller *ddbexportpoller.Poller) (string, error) {
+	if f.arn != "" {
+		return poller.ResolveTableARN(ctx, f.arn)
+	}
+	return poller.ResolveTableARN(ctx, f.name)
+}
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	ddbexportpoller "github.com/aereal/dynamodb-export-poller"
+)
+
+// targetFlags is a set of flags to specify many tables and exports at once
+type targetFlags struct {
+	tableArns  stringsFlag
+	tableNames stringsFlag
+	exportArns stringsFlag
+	fromFile   string
+
+	tablePattern string
+	tableRegexp  string
+	tableTags    stringsFlag
+}
+
+func (f *targetFlags) register(fls *flag.FlagSet) {
+	fls.Var(&f.tableArns, "table-arn", "table ARN to wait exports; comma-separated or repeatable")
+	fls.Var(&f.tableNames, "table", "table name resolved to the ARN in the configured region; comma-separated or repeatable")
+	fls.Var(&f.exportArns, "export-arn", "export ARN to wait; comma-separated or repeatable")
+	fls.StringVar(&f.fromFile, "from-file", "", "read table ARNs, table names or export ARNs line by line from the file (- means stdin)")
+	fls.StringVar(&f.tablePattern, "table-pattern", "", "discover tables whose names match the glob pattern by ListTables")
+	fls.StringVar(&f.tableRegexp, "table-regexp", "", "discover tables whose names match the regular expression by ListTables")
+	fls.Var(&f.tableTags, "table-tag", "discover tables that have the tag in KEY=VALUE or KEY form; comma-separated or repeatable")
+}
+
+// add classifies the target into a table ARN, a table name or an export ARN.
+func (f *targetFlags) add(target string) {
+	switch {
+	case !strings.HasPrefix(target, "arn:"):
+		f.tableNames = append(f.tableNames, target)
+	case strings.Contains(target, "/export/"):
+		f.exportArns = append(f.exportArns, target)
+	default:
+		f.tableArns = append(f.tableArns, target)
+	}
+}
+
+// collect adds positional arguments and targets read from -from-file.
+//
+// Blank lines and lines starting with # in the file are ignored.
+func (f *targetFlags) collect(args []string, stdin io.Reader) error {
+	for _, arg := range args {
+		f.add(arg)
+	}
+	if f.fromFile == "" {
+		return nil
+	}
+	r := stdin
+	if f.fromFile != "-" {
+		fh, err := os.Open(f.fromFile)
+		if err != nil {
+			return err
+		}
+		defer fh.Close()
+		r = fh
+	}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		f.add(line)
+	}
+	return scanner.Err()
+}
+
+func (f *targetFlags) empty() bool {
+	return len(f.tableArns) == 0 && len(f.tableNames) == 0 && len(f.exportArns) == 0 && !f.discovers()
+}
+
+// discovers returns true if any of the flags to discover tables is given.
+func (f *targetFlags) discovers() bool {
+	return f.tablePattern != "" || f.tableRegexp != "" || len(f.tableTags) > 0
+}
+
+func (f *targetFlags) selector() (ddbexportpoller.TableSelector, error) {
+	selector := ddbexportpoller.TableSelector{NamePattern: f.tablePattern}
+	if f.tableRegexp != "" {
+		re, err := regexp.Compile(f.tableRegexp)
+		if err != nil {
+			return ddbexportpoller.TableSelector{}, fmt.Errorf("invalid -table-regexp: %w", err)
+		}
+		selector.NameRegexp = re
+	}
+	if len(f.tableTags) > 0 {
+		selector.Tags = make(map[string]string, len(f.tableTags))
+		for _, tag := range f.tableTags {
+			kv := strings.SplitN(tag, "=", 2)
+			if kv[0] == "" {
+				return ddbexportpoller.TableSelector{}, fmt.Errorf("invalid -table-tag: %q", tag)
+			}
+			if len(kv) == 1 {
+				kv = append(kv, "")
+			}
+			selector.Tags[kv[0]] = kv[1]
+		}
+	}
+	return selector, nil
+}
+
+// resolve resolves the table names and returns the targets to poll.
+func (f *targetFlags) resolve(ctx context.Context, poller *ddbexportpoller.Poller) (ddbexportpoller.Targets, error) {
+	targets := ddbexportpoller.Targets{
+		TableArns:  append([]string(nil), f.tableArns...),
+		ExportArns: append([]string(nil), f.exportArns...),
+	}
+	for _, tableName := range f.tableNames {
+		tableArn, err := poller.ResolveTableARN(ctx, tableName)
+		if err != nil {
+			return ddbexportpoller.Targets{}, err
+		}
+		targets.TableArns = append(targets.TableArns, tableArn)
+	}
+	if f.discovers() {
+		selector, err := f.selector()
+		if err != nil {
+			return ddbexportpoller.Targets{}, err
+		}
+		tableArns, err := poller.DiscoverTables(ctx, selector)
+		if err != nil {
+			return ddbexportpoller.Targets{}, err
+		}
+		if len(tableArns) == 0 {
+			return ddbexportpoller.Targets{}, ddbexportpoller.ErrNoTablesMatched
+		}
+		targets.TableArns = append(targets.TableArns, tableArns...)
+	}
+	return targets, nil
+}
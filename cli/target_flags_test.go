@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTargetFlags_collect(t *testing.T) {
+	f, err := ioutil.TempFile("", "targets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString("# tables\n" + testOtherTableArn + "\n\n  other-table  \n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name    string
+		flags   targetFlags
+		args    []string
+		stdin   string
+		want    targetFlags
+		wantErr bool
+	}{
+		{
+			"positional arguments",
+			targetFlags{tableArns: stringsFlag{testTableArn}},
+			[]string{testOtherTableArn, testExportArn, "my-table"},
+			"",
+			targetFlags{tableArns: stringsFlag{testTableArn, testOtherTableArn}, tableNames: stringsFlag{"my-table"}, exportArns: stringsFlag{testExportArn}},
+			false,
+		},
+		{
+			"from file",
+			targetFlags{fromFile: f.Name()},
+			nil,
+			"",
+			targetFlags{fromFile: f.Name(), tableArns: stringsFlag{testOtherTableArn}, tableNames: stringsFlag{"other-table"}},
+			false,
+		},
+		{
+			"from stdin",
+			targetFlags{fromFile: "-"},
+			[]string{testTableArn},
+			testExportArn + "\n" + testOtherExportArn,
+			targetFlags{fromFile: "-", tableArns: stringsFlag{testTableArn}, exportArns: stringsFlag{testExportArn, testOtherExportArn}},
+			false,
+		},
+		{
+			"file not found",
+			targetFlags{fromFile: f.Name() + ".missing"},
+			nil,
+			"",
+			targetFlags{fromFile: f.Name() + ".missing"},
+			true,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.flags
+			err := got.collect(tc.args, strings.NewReader(tc.stdin))
+			if (err != nil) != tc.wantErr {
+				t.Errorf("error:\n\twantErr=%v\n\tgot=%v", tc.wantErr, err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("targets:\n\twant=%#v\n\tgot=%#v", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"context"
+
+	"github.com/rs/zerolog/log"
+)
+
+func (c *App) runWait(name string, args []string) int {
+	fls := c.newFlagSet(name, name+" [flags] [TABLE_ARN | TABLE_NAME | EXPORT_ARN ...]")
+	pf := &pollerFlags{}
+	pf.register(fls)
+	var (
+		tf     targetFlags
+		ff     exportFilterFlags
+		quorum quorumFlag
+		output string
+	)
+	tf.register(fls)
+	ff.register(fls, "wait on")
+	fls.IntVar(&pf.opts.MinExports, "min-exports", 0, "wait until the number of exports matching the filters appear on each table before waiting for them to finish (zero means waits on in-progress exports only; requires -started-after, -export-time-after or -client-token)")
+	fls.DurationVar(&pf.opts.AppearanceTimeout, "appearance-timeout", 0, "time limit to wait for -min-exports exports to appear (zero means waits forever)")
+	fls.Var(&quorum, "quorum", "number of exports that must complete before returning: all, any or N; the rest are left running, and N greater than the number of exports means all")
+	fls.StringVar(&output, "output", "", "write summaries of waited exports to stdout (json, jsonl, text, go-template=TEMPLATE)")
+	if status, ok := parseFlags(fls, args); !ok {
+		return status
+	}
+	rw, err := newResultWriter(output)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return statusInvalidArguments
+	}
+	if pf.opts.Filter, err = ff.filter(); err != nil {
+		log.Error().Err(err).Send()
+		return statusInvalidArguments
+	}
+	if err := tf.collect(fls.Args(), c.in); err != nil {
+		log.Error().Err(err).Msg("failed to read targets")
+		return statusInvalidArguments
+	}
+	if _, err := tf.selector(); err != nil {
+		log.Error().Err(err).Send()
+		return statusInvalidArguments
+	}
+	if tf.empty() {
+		log.Error().Msg("no targets specified; give -table-arn, -table, -export-arn, -from-file, -table-pattern, -table-regexp, -table-tag or arguments")
+		return statusInvalidArguments
+	}
+
+	pf.opts.Quorum = quorum.n
+
+	ctx := context.Background()
+	poller, err := pf.newPoller(ctx, c.pollerOptions...)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return errorStatus(err)
+	}
+	ctx, cancel := pf.withTimeout(ctx)
+	defer cancel()
+	targets, err := tf.resolve(ctx, poller)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return errorStatus(err)
+	}
+	results, err := poller.Poll(ctx, targets)
+	for _, result := range results {
+		if result != nil && result.Abandoned {
+			log.Warn().Str("exportArn", result.ExportArn).Str("status", string(result.Status)).Msg("export is still running; stopped waiting since the quorum was reached")
+		}
+	}
+	if writeErr := rw.write(c.out, results); writeErr != nil {
+		log.Error().Err(writeErr).Msg("failed to write output")
+		return statusNG
+	}
+	if err != nil {
+		log.Error().Err(err).Send()
+		return errorStatus(err)
+	}
+	return statusOK
+}
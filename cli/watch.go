@@ -0,0 +1,78 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/hashicorp/go-multierror"
+	"github.com/rs/zerolog/log"
+)
+
+func (c *App) runWatch(name string, args []string) int {
+	fls := c.newFlagSet(name, name+" (-table-arn TABLE_ARN | -table TABLE_NAME | -export-arn EXPORT_ARN) [flags]")
+	pf := &pollerFlags{}
+	pf.register(fls)
+	var (
+		tf        tableFlags
+		ff        exportFilterFlags
+		exportArn string
+	)
+	ff.register(fls, "watch")
+	tf.register(fls, "table ARN to watch exports")
+	fls.StringVar(&exportArn, "export-arn", "", "export ARN to watch")
+	if status, ok := parseFlags(fls, args); !ok {
+		return status
+	}
+	if tf.present() == (exportArn != "") {
+		log.Error().Msg("either of one of -table-arn, -table or -export-arn must be specified")
+		return statusInvalidArguments
+	}
+	if err := tf.validate(false); err != nil {
+		log.Error().Err(err).Send()
+		return statusInvalidArguments
+	}
+	var err error
+	if pf.opts.Filter, err = ff.filter(); err != nil {
+		log.Error().Err(err).Send()
+		return statusInvalidArguments
+	}
+
+	ctx := context.Background()
+	poller, err := pf.newPoller(ctx, c.pollerOptions...)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return errorStatus(err)
+	}
+	ctx, cancel := pf.withTimeout(ctx)
+	defer cancel()
+	target := exportArn
+	if tf.present() {
+		if target, err = tf.resolve(ctx, poller); err != nil {
+			log.Error().Err(err).Send()
+			return errorStatus(err)
+		}
+	}
+	events, err := poller.Watch(ctx, target)
+	if err != nil {
+		log.Error().Err(err).Send()
+		return errorStatus(err)
+	}
+	var errs *multierror.Error
+	for ev := range events {
+		if !ev.Settled {
+			fmt.Fprintf(c.out, "%s\t%s\titems=%d\n", ev.ExportArn, ev.Description.ExportStatus, aws.ToInt64(ev.Description.ItemCount))
+			continue
+		}
+		if ev.Err != nil {
+			log.Error().Err(ev.Err).Str("exportArn", ev.ExportArn).Send()
+			errs = multierror.Append(errs, ev.Err)
+			continue
+		}
+		fmt.Fprintf(c.out, "%s\tsettled\n", ev.ExportArn)
+	}
+	if err := errs.ErrorOrNil(); err != nil {
+		return errorStatus(err)
+	}
+	return statusOK
+}
@@ -7,6 +7,6 @@ import (
 )
 
 func main() {
-	app := cli.NewApp(os.Stderr)
+	app := cli.NewApp(os.Stdout, os.Stderr)
 	os.Exit(app.Run(os.Args))
 }
@@ -0,0 +1,193 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/hashicorp/go-multierror"
+	"golang.org/x/sync/semaphore"
+)
+
+// ErrNoTablesMatched is an error that means no tables match the TableSelector
+var ErrNoTablesMatched = errors.New("no tables matched the selector")
+
+// TableSelector selects tables by their names and tags.
+//
+// A table matches the selector only if it satisfies all of the conditions given.
+type TableSelector struct {
+	// NamePattern is a glob pattern of table names in the syntax of path.Match
+	NamePattern string
+
+	// NameRegexp is a regular expression of table names
+	NameRegexp *regexp.Regexp
+
+	// Tags are the tags the table must have. An empty value matches any value of the tag.
+	Tags map[string]string
+}
+
+func (s TableSelector) validate() error {
+	if s.NamePattern == "" {
+		return nil
+	}
+	if _, err := path.Match(s.NamePattern, ""); err != nil {
+		return fmt.Errorf("invalid table name pattern %q: %w", s.NamePattern, err)
+	}
+	return nil
+}
+
+// MatchName returns true if the table name matches NamePattern and NameRegexp.
+func (s TableSelector) MatchName(tableName string) bool {
+	if s.NamePattern != "" {
+		if ok, _ := path.Match(s.NamePattern, tableName); !ok {
+			return false
+		}
+	}
+	if s.NameRegexp != nil && !s.NameRegexp.MatchString(tableName) {
+		return false
+	}
+	return true
+}
+
+// MatchTags returns true if the tags contain all of Tags.
+func (s TableSelector) MatchTags(tags map[string]string) bool {
+	for key, want := range s.Tags {
+		got, ok := tags[key]
+		if !ok || (want != "" && got != want) {
+			return false
+		}
+	}
+	return true
+}
+
+// DiscoverTables returns ARNs of the tables that match the selector in the region the client is configured with.
+//
+// Tables are listed by ListTables, and the tables whose names match are described concurrently up to PollerOptions.Concurrency to check their tags.
+func (p *Poller) DiscoverTables(ctx context.Context, selector TableSelector) ([]string, error) {
+	if err := selector.validate(); err != nil {
+		return nil, err
+	}
+	tableNames, err := p.listTables(ctx)
+	if err != nil {
+		return nil, err
+	}
+	matchedNames := make([]string, 0, len(tableNames))
+	for _, tableName := range tableNames {
+		if selector.MatchName(tableName) {
+			matchedNames = append(matchedNames, tableName)
+		}
+	}
+
+	sem := semaphore.NewWeighted(p.options.Concurrency)
+	meg := &multierror.Group{}
+	tableArns := make([]string, len(matchedNames))
+	for i, tableName := range matchedNames {
+		i, tableName := i, tableName
+		if err := sem.Acquire(ctx, semaphoreWorkerAmount); err != nil {
+			_ = meg.Wait()
+			return nil, err
+		}
+		meg.Go(func() error {
+			defer sem.Release(semaphoreWorkerAmount)
+			tableArn, err := p.ResolveTableARN(ctx, tableName)
+			if err != nil {
+				return err
+			}
+			if len(selector.Tags) > 0 {
+				tags, err := p.listTags(ctx, tableArn)
+				if err != nil {
+					return err
+				}
+				if !selector.MatchTags(tags) {
+					return nil
+				}
+			}
+			tableArns[i] = tableArn
+			return nil
+		})
+	}
+	if err := meg.Wait().ErrorOrNil(); err != nil {
+		return nil, err
+	}
+	matched := tableArns[:0]
+	for _, tableArn := range tableArns {
+		if tableArn != "" {
+			matched = append(matched, tableArn)
+		}
+	}
+	return matched, nil
+}
+
+// PollExportsOnTables polls in-progress export jobs on the tables that match the selector.
+//
+// It is a shorthand for DiscoverTables and Poll, so the export jobs share PollerOptions.Concurrency, PollerOptions.Timeout and PollerOptions.Quorum.
+// PollerOptions.Timeout covers discovering the tables as well.
+// ErrNoTablesMatched is returned if no tables match the selector.
+func (p *Poller) PollExportsOnTables(ctx context.Context, selector TableSelector) ([]*ExportResult, error) {
+	ctx, cancel := p.options.withTimeout(ctx)
+	defer cancel()
+	tableArns, err := p.DiscoverTables(ctx, selector)
+	if err != nil {
+		return nil, err
+	}
+	if len(tableArns) == 0 {
+		return nil, ErrNoTablesMatched
+	}
+	return p.Poll(ctx, Targets{TableArns: tableArns})
+}
+
+func (p *Poller) listTables(ctx context.Context) ([]string, error) {
+	input := &dynamodb.ListTablesInput{}
+	var tableNames []string
+	for {
+		out, err := p.listTablesPage(ctx, input)
+		if err != nil {
+			p.options.observer().OnAPIError(ctx, "ListTables", err)
+			return nil, fmt.Errorf("ListTables(): %w", err)
+		}
+		tableNames = append(tableNames, out.TableNames...)
+		if out.LastEvaluatedTableName == nil || *out.LastEvaluatedTableName == "" {
+			break
+		}
+		input.ExclusiveStartTableName = out.LastEvaluatedTableName
+	}
+	return tableNames, nil
+}
+
+func (p *Poller) listTags(ctx context.Context, tableArn string) (map[string]string, error) {
+	input := &dynamodb.ListTagsOfResourceInput{ResourceArn: aws.String(tableArn)}
+	tags := map[string]string{}
+	for {
+		out, err := p.listTagsPage(ctx, tableArn, input)
+		if err != nil {
+			p.options.observer().OnAPIError(ctx, "ListTagsOfResource", err)
+			return nil, fmt.Errorf("ListTagsOfResource(%s): %w", tableArn, err)
+		}
+		for _, tag := range out.Tags {
+			tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+		}
+		if out.NextToken == nil || *out.NextToken == "" {
+			break
+		}
+		input.NextToken = out.NextToken
+	}
+	return tags, nil
+}
+
+func (p *Poller) listTablesPage(ctx context.Context, input *dynamodb.ListTablesInput) (*dynamodb.ListTablesOutput, error) {
+	reqCtx, cancel := p.options.withRequestTimeout(ctx)
+	defer cancel()
+	out, err := p.client.ListTables(reqCtx, input)
+	return out, p.options.requestTimeoutError(ctx, reqCtx, "ListTables", err)
+}
+
+func (p *Poller) listTagsPage(ctx context.Context, tableArn string, input *dynamodb.ListTagsOfResourceInput) (*dynamodb.ListTagsOfResourceOutput, error) {
+	reqCtx, cancel := p.options.withRequestTimeout(ctx)
+	defer cancel()
+	out, err := p.clientFor(tableArn).ListTagsOfResource(reqCtx, input)
+	return out, p.options.requestTimeoutError(ctx, reqCtx, "ListTagsOfResource", err)
+}
@@ -0,0 +1,223 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/aereal/dynamodb-export-poller/internal/ddb"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/golang/mock/gomock"
+)
+
+func TestTableSelector_MatchName(t *testing.T) {
+	testCases := []struct {
+		name      string
+		selector  TableSelector
+		tableName string
+		want      bool
+	}{
+		{"empty selector", TableSelector{}, "prod-users", true},
+		{"glob matched", TableSelector{NamePattern: "prod-*"}, "prod-users", true},
+		{"glob not matched", TableSelector{NamePattern: "prod-*"}, "dev-users", false},
+		{"regexp matched", TableSelector{NameRegexp: regexp.MustCompile(`^(prod|stg)-`)}, "stg-users", true},
+		{"regexp not matched", TableSelector{NameRegexp: regexp.MustCompile(`^(prod|stg)-`)}, "dev-users", false},
+		{"both must match", TableSelector{NamePattern: "*-users", NameRegexp: regexp.MustCompile(`^prod-`)}, "prod-orders", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.selector.MatchName(tc.tableName); got != tc.want {
+				t.Errorf("want=%v got=%v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestTableSelector_MatchTags(t *testing.T) {
+	tags := map[string]string{"env": "prod", "team": "data"}
+	testCases := []struct {
+		name     string
+		selector TableSelector
+		want     bool
+	}{
+		{"no tags required", TableSelector{}, true},
+		{"value matched", TableSelector{Tags: map[string]string{"env": "prod"}}, true},
+		{"value not matched", TableSelector{Tags: map[string]string{"env": "dev"}}, false},
+		{"any value", TableSelector{Tags: map[string]string{"team": ""}}, true},
+		{"missing key", TableSelector{Tags: map[string]string{"owner": ""}}, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.selector.MatchTags(tags); got != tc.want {
+				t.Errorf("want=%v got=%v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestPoller_DiscoverTables(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	tableArnOf := func(tableName string) string {
+		return "arn:aws:dynamodb:us-east-1:123456789012:table/" + tableName
+	}
+	testCases := []struct {
+		name     string
+		selector TableSelector
+		onMock   func(mockClient *ddb.MockClient)
+		want     []string
+		wantErr  error
+	}{
+		{
+			"invalid pattern",
+			TableSelector{NamePattern: "["},
+			func(mockClient *ddb.MockClient) {},
+			nil,
+			errors.New(`invalid table name pattern "[": syntax error in pattern`),
+		},
+		{
+			"name pattern across pages",
+			TableSelector{NamePattern: "prod-*"},
+			func(mockClient *ddb.MockClient) {
+				listTablesPage(mockClient, "", []string{"dev-users", "prod-users"}, "prod-users").Times(1)
+				listTablesPage(mockClient, "prod-users", []string{"prod-orders"}, "").Times(1)
+				describeTableOf(mockClient, "prod-users", tableArnOf("prod-users")).Times(1)
+				describeTableOf(mockClient, "prod-orders", tableArnOf("prod-orders")).Times(1)
+			},
+			[]string{tableArnOf("prod-users"), tableArnOf("prod-orders")},
+			nil,
+		},
+		{
+			"tags",
+			TableSelector{NamePattern: "prod-*", Tags: map[string]string{"team": "data"}},
+			func(mockClient *ddb.MockClient) {
+				listTablesPage(mockClient, "", []string{"prod-users", "prod-orders"}, "").Times(1)
+				describeTableOf(mockClient, "prod-users", tableArnOf("prod-users")).Times(1)
+				describeTableOf(mockClient, "prod-orders", tableArnOf("prod-orders")).Times(1)
+				mockClient.EXPECT().
+					ListTagsOfResource(gomock.Any(), &dynamodb.ListTagsOfResourceInput{ResourceArn: aws.String(tableArnOf("prod-users"))}).
+					Return(&dynamodb.ListTagsOfResourceOutput{Tags: []types.Tag{{Key: aws.String("team"), Value: aws.String("web")}}, NextToken: aws.String("next")}, nil).
+					Times(1)
+				mockClient.EXPECT().
+					ListTagsOfResource(gomock.Any(), &dynamodb.ListTagsOfResourceInput{ResourceArn: aws.String(tableArnOf("prod-users")), NextToken: aws.String("next")}).
+					Return(&dynamodb.ListTagsOfResourceOutput{Tags: []types.Tag{{Key: aws.String("env"), Value: aws.String("prod")}}}, nil).
+					Times(1)
+				mockClient.EXPECT().
+					ListTagsOfResource(gomock.Any(), &dynamodb.ListTagsOfResourceInput{ResourceArn: aws.String(tableArnOf("prod-orders"))}).
+					Return(&dynamodb.ListTagsOfResourceOutput{Tags: []types.Tag{{Key: aws.String("team"), Value: aws.String("data")}}}, nil).
+					Times(1)
+			},
+			[]string{tableArnOf("prod-orders")},
+			nil,
+		},
+		{
+			"ListTables error",
+			TableSelector{},
+			func(mockClient *ddb.MockClient) {
+				mockClient.EXPECT().ListTables(gomock.Any(), gomock.Any()).Return(nil, errors.New("oops")).Times(1)
+			},
+			nil,
+			errors.New("ListTables(): oops"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := ddb.NewMockClient(ctrl)
+			tc.onMock(mockClient)
+			poller, err := NewPoller(PollerOptions{Concurrency: 2}, WithClient(mockClient))
+			if err != nil {
+				t.Fatalf("NewPoller(): %s", err)
+			}
+			got, err := poller.DiscoverTables(context.Background(), tc.selector)
+			assertErr(t, err, tc.wantErr)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("table ARNs:\n\twant=%#v\n\tgot=%#v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestPoller_PollExportsOnTables(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	tableArn := "arn:aws:dynamodb:us-east-1:123456789012:table/prod-users"
+	exportArn := tableArn + "/export/1"
+	testCases := []struct {
+		name     string
+		selector TableSelector
+		onMock   func(mockClient *ddb.MockClient)
+		wantArns []string
+		wantErr  error
+	}{
+		{
+			"no tables matched",
+			TableSelector{NamePattern: "stg-*"},
+			func(mockClient *ddb.MockClient) {
+				listTablesPage(mockClient, "", []string{"prod-users"}, "").Times(1)
+			},
+			nil,
+			ErrNoTablesMatched,
+		},
+		{
+			"ok",
+			TableSelector{NamePattern: "prod-*"},
+			func(mockClient *ddb.MockClient) {
+				listTablesPage(mockClient, "", []string{"prod-users"}, "").Times(1)
+				describeTableOf(mockClient, "prod-users", tableArn).Times(1)
+				listExportsOf(mockClient, tableArn, []types.ExportSummary{{ExportArn: aws.String(exportArn), ExportStatus: types.ExportStatusInProgress}}).Times(1)
+				describeExportOf(mockClient, exportArn, &types.ExportDescription{ExportArn: aws.String(exportArn), ExportStatus: types.ExportStatusCompleted}).Times(1)
+			},
+			[]string{exportArn},
+			nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := ddb.NewMockClient(ctrl)
+			tc.onMock(mockClient)
+			poller, err := NewPoller(PollerOptions{Concurrency: 2, MaxAttempts: 1}, WithClient(mockClient))
+			if err != nil {
+				t.Fatalf("NewPoller(): %s", err)
+			}
+			results, err := poller.PollExportsOnTables(context.Background(), tc.selector)
+			assertErr(t, err, tc.wantErr)
+			var gotArns []string
+			for _, r := range results {
+				gotArns = append(gotArns, r.ExportArn)
+			}
+			if !reflect.DeepEqual(gotArns, tc.wantArns) {
+				t.Errorf("results:\n\twant=%v\n\tgot=%v", tc.wantArns, gotArns)
+			}
+		})
+	}
+}
+
+func listTablesPage(mockClient *ddb.MockClient, startTableName string, tableNames []string, lastTableName string) *gomock.Call {
+	input := &dynamodb.ListTablesInput{}
+	if startTableName != "" {
+		input.ExclusiveStartTableName = aws.String(startTableName)
+	}
+	out := &dynamodb.ListTablesOutput{TableNames: tableNames}
+	if lastTableName != "" {
+		out.LastEvaluatedTableName = aws.String(lastTableName)
+	}
+	return mockClient.EXPECT().ListTables(gomock.Any(), input).Return(out, nil)
+}
+
+func describeTableOf(mockClient *ddb.MockClient, tableName string, tableArn string) *gomock.Call {
+	return mockClient.EXPECT().
+		DescribeTable(gomock.Any(), &dynamodb.DescribeTableInput{TableName: aws.String(tableName)}).
+		Return(&dynamodb.DescribeTableOutput{Table: &types.TableDescription{TableName: aws.String(tableName), TableArn: aws.String(tableArn)}}, nil)
+}
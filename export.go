@@ -0,0 +1,144 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/rs/zerolog/log"
+)
+
+var (
+	// ErrS3BucketRequired is an error that means mandatory S3 bucket is not passed
+	ErrS3BucketRequired = errors.New("S3 bucket required")
+
+	// ErrIncrementalExportWindowRequired is an error that means the time range of an incremental export is not passed
+	ErrIncrementalExportWindowRequired = errors.New("export from time and export to time required for incremental export")
+)
+
+// ExportRequest is a set of parameters to start an export job.
+type ExportRequest struct {
+	// TableArn is the ARN of the table to export
+	TableArn string
+
+	// S3Bucket is the name of the bucket to export the table data to
+	S3Bucket string
+
+	// S3Prefix is the key prefix of the exported data
+	S3Prefix string
+
+	// S3BucketOwner is the ID of the AWS account that owns the bucket
+	S3BucketOwner string
+
+	// S3SseAlgorithm is the type of encryption used for the exported data
+	S3SseAlgorithm types.S3SseAlgorithm
+
+	// S3SseKmsKeyId is the ID of the KMS key used for the exported data
+	S3SseKmsKeyId string
+
+	// ExportFormat is the format of the exported data
+	ExportFormat types.ExportFormat
+
+	// ExportTime is the point in time of the table data to export.
+	//
+	// Zero means the current time.
+	ExportTime time.Time
+
+	// ClientToken is used to make the request idempotent
+	ClientToken string
+
+	// ExportType is the type of the export job. The default is FULL_EXPORT.
+	ExportType types.ExportType
+
+	// ExportFromTime is the start of the time range of table changes to export. It is required for incremental exports.
+	ExportFromTime time.Time
+
+	// ExportToTime is the end of the time range of table changes to export. It is required for incremental exports.
+	ExportToTime time.Time
+
+	// ExportViewType is the view of the exported item images. It is used only for incremental exports.
+	ExportViewType types.ExportViewType
+}
+
+func (r ExportRequest) validate() error {
+	if _, err := ParseTableARN(r.TableArn); err != nil {
+		return err
+	}
+	if r.S3Bucket == "" {
+		return ErrS3BucketRequired
+	}
+	if r.ExportType == types.ExportTypeIncrementalExport && (r.ExportFromTime.IsZero() || r.ExportToTime.IsZero()) {
+		return ErrIncrementalExportWindowRequired
+	}
+	return nil
+}
+
+func (r ExportRequest) input() *dynamodb.ExportTableToPointInTimeInput {
+	input := &dynamodb.ExportTableToPointInTimeInput{
+		TableArn:       aws.String(r.TableArn),
+		S3Bucket:       aws.String(r.S3Bucket),
+		S3SseAlgorithm: r.S3SseAlgorithm,
+		ExportFormat:   r.ExportFormat,
+	}
+	if r.S3Prefix != "" {
+		input.S3Prefix = aws.String(r.S3Prefix)
+	}
+	if r.S3BucketOwner != "" {
+		input.S3BucketOwner = aws.String(r.S3BucketOwner)
+	}
+	if r.S3SseKmsKeyId != "" {
+		input.S3SseKmsKeyId = aws.String(r.S3SseKmsKeyId)
+	}
+	if !r.ExportTime.IsZero() {
+		input.ExportTime = aws.Time(r.ExportTime)
+	}
+	if r.ClientToken != "" {
+		input.ClientToken = aws.String(r.ClientToken)
+	}
+	input.ExportType = r.ExportType
+	if r.ExportType == types.ExportTypeIncrementalExport {
+		input.IncrementalExportSpecification = &types.IncrementalExportSpecification{
+			ExportFromTime: aws.Time(r.ExportFromTime),
+			ExportToTime:   aws.Time(r.ExportToTime),
+			ExportViewType: r.ExportViewType,
+		}
+	}
+	return input
+}
+
+// ExportAndWait starts an export job and polls it until it finishes.
+//
+// You can configure polling behaviors through PollerOptions.
+//
+// The result is returned even if an error occurred as long as the export job started.
+func (p *Poller) ExportAndWait(ctx context.Context, req ExportRequest) (*ExportResult, error) {
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := p.options.withTimeout(ctx)
+	defer cancel()
+	description, err := p.startExport(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	exportArn := aws.ToString(description.ExportArn)
+	log.Debug().Str("exportArn", exportArn).Msg("export started")
+	return p.pollExportWithRetries(ctx, newExportTracker(exportArn))
+}
+
+func (p *Poller) startExport(ctx context.Context, req ExportRequest) (*types.ExportDescription, error) {
+	reqCtx, cancel := p.options.withRequestTimeout(ctx)
+	defer cancel()
+	out, err := p.clientFor(req.TableArn).ExportTableToPointInTime(reqCtx, req.input())
+	err = p.options.requestTimeoutError(ctx, reqCtx, "ExportTableToPointInTime", err)
+	if err != nil {
+		p.options.observer().OnAPIError(ctx, "ExportTableToPointInTime", err)
+		return nil, fmt.Errorf("ExportTableToPointInTime(): %w", err)
+	}
+	return out.ExportDescription, nil
+}
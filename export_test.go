@@ -0,0 +1,135 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aereal/dynamodb-export-poller/internal/ddb"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/golang/mock/gomock"
+)
+
+func TestPoller_ExportAndWait(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	exportArn := "arn:aws:dynamodb:us-east-1:123456789012:table/my-table/export/9012-3456"
+	exportTime := time.Date(2022, time.August, 1, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name       string
+		req        ExportRequest
+		onMock     func(mockClient *ddb.MockClient)
+		wantStatus types.ExportStatus
+		wantErr    error
+	}{
+		{
+			"empty table ARN",
+			ExportRequest{S3Bucket: "my-bucket"},
+			func(mockClient *ddb.MockClient) {},
+			"",
+			ErrTableArnRequired,
+		},
+		{
+			"empty bucket",
+			ExportRequest{TableArn: "arn:aws:dynamodb:us-east-1:123456789012:table/my-table"},
+			func(mockClient *ddb.MockClient) {},
+			"",
+			ErrS3BucketRequired,
+		},
+		{
+			"incremental export without window",
+			ExportRequest{TableArn: "arn:aws:dynamodb:us-east-1:123456789012:table/my-table", S3Bucket: "my-bucket", ExportType: types.ExportTypeIncrementalExport, ExportToTime: exportTime},
+			func(mockClient *ddb.MockClient) {},
+			"",
+			ErrIncrementalExportWindowRequired,
+		},
+		{
+			"export completed",
+			ExportRequest{
+				TableArn:       "arn:aws:dynamodb:us-east-1:123456789012:table/my-table",
+				S3Bucket:       "my-bucket",
+				S3Prefix:       "exports",
+				S3BucketOwner:  "210987654321",
+				S3SseAlgorithm: types.S3SseAlgorithmKms,
+				S3SseKmsKeyId:  "my-key",
+				ExportFormat:   types.ExportFormatIon,
+				ExportTime:     exportTime,
+				ClientToken:    "token",
+			},
+			func(mockClient *ddb.MockClient) {
+				want := &dynamodb.ExportTableToPointInTimeInput{
+					TableArn:       aws.String("arn:aws:dynamodb:us-east-1:123456789012:table/my-table"),
+					S3Bucket:       aws.String("my-bucket"),
+					S3Prefix:       aws.String("exports"),
+					S3BucketOwner:  aws.String("210987654321"),
+					S3SseAlgorithm: types.S3SseAlgorithmKms,
+					S3SseKmsKeyId:  aws.String("my-key"),
+					ExportFormat:   types.ExportFormatIon,
+					ExportTime:     aws.Time(exportTime),
+					ClientToken:    aws.String("token"),
+				}
+				seq(
+					mockClient.EXPECT().
+						ExportTableToPointInTime(gomock.Any(), want).
+						Return(&dynamodb.ExportTableToPointInTimeOutput{ExportDescription: &types.ExportDescription{ExportArn: aws.String(exportArn), ExportStatus: types.ExportStatusInProgress}}, nil).
+						Times(1),
+					describeExport(mockClient, &types.ExportDescription{ExportArn: aws.String(exportArn), ExportStatus: types.ExportStatusCompleted}).Times(1),
+				)
+			},
+			types.ExportStatusCompleted,
+			nil,
+		},
+		{
+			"failed to start",
+			ExportRequest{TableArn: "arn:aws:dynamodb:us-east-1:123456789012:table/my-table", S3Bucket: "my-bucket"},
+			func(mockClient *ddb.MockClient) {
+				mockClient.EXPECT().
+					ExportTableToPointInTime(gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("oops")).
+					Times(1)
+			},
+			"",
+			errors.New("ExportTableToPointInTime(): oops"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := ddb.NewMockClient(ctrl)
+			tc.onMock(mockClient)
+			poller, err := NewPoller(PollerOptions{Concurrency: 1, MaxAttempts: 1}, WithClient(mockClient))
+			if err != nil {
+				t.Fatalf("NewPoller(): %s", err)
+			}
+			got, err := poller.ExportAndWait(context.Background(), tc.req)
+			assertErr(t, err, tc.wantErr)
+			if err != nil {
+				return
+			}
+			if got.ExportArn != exportArn {
+				t.Errorf("ExportArn:\n\twant=%s\n\tgot=%s", exportArn, got.ExportArn)
+			}
+			if got.Status != tc.wantStatus {
+				t.Errorf("Status:\n\twant=%s\n\tgot=%s", tc.wantStatus, got.Status)
+			}
+		})
+	}
+}
+
+func TestExportRequest_input(t *testing.T) {
+	got := ExportRequest{TableArn: "arn:aws:dynamodb:us-east-1:123456789012:table/my-table", S3Bucket: "my-bucket"}.input()
+	want := &dynamodb.ExportTableToPointInTimeInput{
+		TableArn: aws.String("arn:aws:dynamodb:us-east-1:123456789012:table/my-table"),
+		S3Bucket: aws.String("my-bucket"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("input:\n\twant=%#v\n\tgot=%#v", want, got)
+	}
+}
@@ -0,0 +1,105 @@
+package ddbexportpoller
+
+import (
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ExportFilter selects exports by their attributes.
+//
+// Zero value fields are ignored, so the zero ExportFilter matches all exports.
+type ExportFilter struct {
+	// Statuses selects exports that have one of the statuses
+	Statuses []types.ExportStatus
+
+	// ExportTypes selects exports that have one of the types
+	ExportTypes []types.ExportType
+
+	// StartedAfter selects exports started at or after the time
+	StartedAfter time.Time
+
+	// StartedBefore selects exports started before the time
+	StartedBefore time.Time
+
+	// S3Bucket selects exports to the bucket
+	S3Bucket string
+
+	// S3Prefix selects exports whose S3 key prefix starts with the prefix
+	S3Prefix string
+
+	// ClientToken selects exports started with the client token
+	ClientToken string
+
+	// ExportTimeAfter selects exports whose point in time to export is at or after the time
+	ExportTimeAfter time.Time
+}
+
+// MatchSummary reports whether the export summary satisfies the filter as far as the summary tells.
+//
+// An export that matches the summary may still be rejected by Match.
+func (f ExportFilter) MatchSummary(summary types.ExportSummary) bool {
+	return f.matchStatus(summary.ExportStatus) && f.matchType(summary.ExportType)
+}
+
+// Match reports whether the export satisfies the filter.
+func (f ExportFilter) Match(d *types.ExportDescription) bool {
+	if !f.matchStatus(d.ExportStatus) || !f.matchType(d.ExportType) {
+		return false
+	}
+	startTime := aws.ToTime(d.StartTime)
+	if !f.StartedAfter.IsZero() && startTime.Before(f.StartedAfter) {
+		return false
+	}
+	if !f.StartedBefore.IsZero() && !startTime.Before(f.StartedBefore) {
+		return false
+	}
+	if f.S3Bucket != "" && aws.ToString(d.S3Bucket) != f.S3Bucket {
+		return false
+	}
+	if f.S3Prefix != "" && !strings.HasPrefix(aws.ToString(d.S3Prefix), f.S3Prefix) {
+		return false
+	}
+	if f.ClientToken != "" && aws.ToString(d.ClientToken) != f.ClientToken {
+		return false
+	}
+	if !f.ExportTimeAfter.IsZero() && aws.ToTime(d.ExportTime).Before(f.ExportTimeAfter) {
+		return false
+	}
+	return true
+}
+
+// needsDescription reports whether the filter has conditions that the export summary cannot tell.
+func (f ExportFilter) needsDescription() bool {
+	return !f.StartedAfter.IsZero() || !f.StartedBefore.IsZero() || f.S3Bucket != "" || f.S3Prefix != "" || f.ClientToken != "" || !f.ExportTimeAfter.IsZero()
+}
+
+func (f ExportFilter) matchStatus(status types.ExportStatus) bool {
+	if len(f.Statuses) == 0 {
+		return true
+	}
+	for _, s := range f.Statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (f ExportFilter) matchType(exportType types.ExportType) bool {
+	if len(f.ExportTypes) == 0 {
+		return true
+	}
+	// exports started before incremental exports are introduced have no type and they are full exports
+	if exportType == "" {
+		exportType = types.ExportTypeFullExport
+	}
+	for _, t := range f.ExportTypes {
+		if t == exportType {
+			return true
+		}
+	}
+	return false
+}
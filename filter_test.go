@@ -0,0 +1,70 @@
+package ddbexportpoller
+
+import (
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestExportFilter_Match(t *testing.T) {
+	startTime := time.Date(2023, time.October, 1, 0, 0, 0, 0, time.UTC)
+	description := &types.ExportDescription{
+		ExportStatus: types.ExportStatusCompleted,
+		ExportType:   types.ExportTypeIncrementalExport,
+		StartTime:    aws.Time(startTime),
+		S3Bucket:     aws.String("my-bucket"),
+		S3Prefix:     aws.String("exports/my-table/2023"),
+		ClientToken:  aws.String("nightly-20231001"),
+	}
+	testCases := []struct {
+		name   string
+		filter ExportFilter
+		want   bool
+	}{
+		{"zero", ExportFilter{}, true},
+		{"status matched", ExportFilter{Statuses: []types.ExportStatus{types.ExportStatusFailed, types.ExportStatusCompleted}}, true},
+		{"status unmatched", ExportFilter{Statuses: []types.ExportStatus{types.ExportStatusInProgress}}, false},
+		{"type matched", ExportFilter{ExportTypes: []types.ExportType{types.ExportTypeIncrementalExport}}, true},
+		{"type unmatched", ExportFilter{ExportTypes: []types.ExportType{types.ExportTypeFullExport}}, false},
+		{"started after", ExportFilter{StartedAfter: startTime}, true},
+		{"not started after", ExportFilter{StartedAfter: startTime.Add(time.Second)}, false},
+		{"started before", ExportFilter{StartedBefore: startTime.Add(time.Second)}, true},
+		{"not started before", ExportFilter{StartedBefore: startTime}, false},
+		{"bucket matched", ExportFilter{S3Bucket: "my-bucket"}, true},
+		{"bucket unmatched", ExportFilter{S3Bucket: "other-bucket"}, false},
+		{"prefix matched", ExportFilter{S3Prefix: "exports/my-table/"}, true},
+		{"prefix unmatched", ExportFilter{S3Prefix: "exports/other-table/"}, false},
+		{"client token matched", ExportFilter{ClientToken: "nightly-20231001"}, true},
+		{"client token unmatched", ExportFilter{ClientToken: "manual"}, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.filter.Match(description); got != tc.want {
+				t.Errorf("Match():\n\twant=%v\n\tgot=%v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestExportFilter_MatchSummary(t *testing.T) {
+	testCases := []struct {
+		name    string
+		filter  ExportFilter
+		summary types.ExportSummary
+		want    bool
+	}{
+		{"zero", ExportFilter{}, types.ExportSummary{ExportStatus: types.ExportStatusCompleted}, true},
+		{"status unmatched", ExportFilter{Statuses: []types.ExportStatus{types.ExportStatusFailed}}, types.ExportSummary{ExportStatus: types.ExportStatusCompleted}, false},
+		{"untyped export is full export", ExportFilter{ExportTypes: []types.ExportType{types.ExportTypeFullExport}}, types.ExportSummary{}, true},
+		{"time range is not checked", ExportFilter{StartedAfter: time.Now()}, types.ExportSummary{}, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.filter.MatchSummary(tc.summary); got != tc.want {
+				t.Errorf("MatchSummary():\n\twant=%v\n\tgot=%v", tc.want, got)
+			}
+		})
+	}
+}
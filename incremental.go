@@ -0,0 +1,241 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// MinIncrementalExportWindow is the shortest time range an incremental export can cover
+	MinIncrementalExportWindow = time.Minute * 15
+
+	// MaxIncrementalExportWindow is the longest time range an incremental export can cover
+	MaxIncrementalExportWindow = time.Hour * 24
+)
+
+var (
+	// ErrNoBaseExport is an error that means no completed export is found to continue the incremental export from
+	ErrNoBaseExport = errors.New("no completed export found to continue incremental export from")
+
+	// ErrIncrementalExportWindowTooShort is an error that means the next incremental export window is shorter than MinIncrementalExportWindow
+	ErrIncrementalExportWindowTooShort = fmt.Errorf("incremental export window must be %s or longer", MinIncrementalExportWindow)
+
+	// ErrExportInProgress is an error that means the next incremental export cannot be planned because another export is ongoing
+	ErrExportInProgress = errors.New("another export is in progress on the table")
+)
+
+// ExportWindow is a time range of table changes covered by an incremental export.
+type ExportWindow struct {
+	// From is the inclusive start of the window
+	From time.Time
+
+	// To is the exclusive end of the window
+	To time.Time
+}
+
+func (w ExportWindow) String() string {
+	return fmt.Sprintf("[%s, %s)", w.From.Format(time.RFC3339), w.To.Format(time.RFC3339))
+}
+
+// ExportWindowError is an error that means consecutive incremental export windows are not contiguous.
+type ExportWindowError struct {
+	// Gaps are the time ranges covered by no exports
+	Gaps []ExportWindow
+
+	// Overlaps are the time ranges covered by more than one exports
+	Overlaps []ExportWindow
+}
+
+func (e *ExportWindowError) Error() string {
+	var b strings.Builder
+	b.WriteString("incremental export windows are not contiguous:")
+	for _, w := range e.Gaps {
+		fmt.Fprintf(&b, " gap=%s", w)
+	}
+	for _, w := range e.Overlaps {
+		fmt.Fprintf(&b, " overlap=%s", w)
+	}
+	return b.String()
+}
+
+// IncrementalExportPlan is a plan of the next incremental export on the table.
+type IncrementalExportPlan struct {
+	// TableArn is the ARN of the table to export
+	TableArn string
+
+	// BaseExportArn is the ARN of the last completed export that the planned window continues from
+	BaseExportArn string
+
+	// Window is the time range of table changes the next incremental export covers
+	Window ExportWindow
+}
+
+// PlanIncrementalExport computes the next incremental export window that continues from the last completed export on the table.
+//
+// The window starts from the point the last completed export covered up to and ends at the until or MaxIncrementalExportWindow later, whichever is earlier.
+// The zero until means the current time.
+func (p *Poller) PlanIncrementalExport(ctx context.Context, tableArn string, until time.Time) (*IncrementalExportPlan, error) {
+	if _, err := ParseTableARN(tableArn); err != nil {
+		return nil, err
+	}
+	if until.IsZero() {
+		until = time.Now()
+	}
+	summaries, err := p.listExports(ctx, tableArn)
+	if err != nil {
+		return nil, err
+	}
+	completedArns := make([]string, 0, len(summaries))
+	for _, summary := range summaries {
+		switch summary.ExportStatus {
+		case types.ExportStatusInProgress:
+			return nil, ErrExportInProgress
+		case types.ExportStatusCompleted:
+			completedArns = append(completedArns, aws.ToString(summary.ExportArn))
+		}
+	}
+	descriptions, err := p.describeExports(ctx, completedArns)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		base       *types.ExportDescription
+		coveredTil time.Time
+	)
+	for _, d := range descriptions {
+		if t := exportCoveredUntil(d); t.After(coveredTil) {
+			base, coveredTil = d, t
+		}
+	}
+	if base == nil {
+		return nil, ErrNoBaseExport
+	}
+	to := until
+	if limit := coveredTil.Add(MaxIncrementalExportWindow); to.After(limit) {
+		to = limit
+	}
+	if to.Sub(coveredTil) < MinIncrementalExportWindow {
+		return nil, ErrIncrementalExportWindowTooShort
+	}
+	return &IncrementalExportPlan{
+		TableArn:      tableArn,
+		BaseExportArn: aws.ToString(base.ExportArn),
+		Window:        ExportWindow{From: coveredTil, To: to},
+	}, nil
+}
+
+// IncrementalExportAndWait plans the next incremental export, starts it and polls it until it finishes.
+//
+// The window is computed by PlanIncrementalExport with req.ExportToTime as the until, so ExportType, ExportFromTime and ExportToTime in req are overwritten.
+// After the export completes, IncrementalExportAndWait verifies the incremental export windows on the table are contiguous.
+// PollerOptions.Timeout limits the whole of them.
+func (p *Poller) IncrementalExportAndWait(ctx context.Context, req ExportRequest) (*ExportResult, error) {
+	// the window is not validated since it is planned below
+	req.ExportType = ""
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := p.options.withTimeout(ctx)
+	defer cancel()
+	plan, err := p.PlanIncrementalExport(ctx, req.TableArn, req.ExportToTime)
+	if err != nil {
+		return nil, err
+	}
+	log.Debug().Str("tableArn", req.TableArn).Str("baseExportArn", plan.BaseExportArn).Stringer("window", plan.Window).Msg("incremental export planned")
+	req.ExportType = types.ExportTypeIncrementalExport
+	req.ExportFromTime = plan.Window.From
+	req.ExportToTime = plan.Window.To
+	result, err := p.ExportAndWait(ctx, req)
+	if err != nil {
+		return result, err
+	}
+	if err := p.VerifyIncrementalExports(ctx, req.TableArn); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// VerifyIncrementalExports verifies there are no gaps or overlaps between consecutive windows of the completed incremental exports on the table.
+//
+// Only the windows of the current chain, which start at or after the point in time of the latest completed full export, are verified.
+// ExportWindowError is returned if the windows are not contiguous.
+func (p *Poller) VerifyIncrementalExports(ctx context.Context, tableArn string) error {
+	if _, err := ParseTableARN(tableArn); err != nil {
+		return err
+	}
+	summaries, err := p.listExports(ctx, tableArn)
+	if err != nil {
+		return err
+	}
+	exportArns := make([]string, 0, len(summaries))
+	for _, summary := range summaries {
+		if summary.ExportStatus != types.ExportStatusCompleted {
+			continue
+		}
+		exportArns = append(exportArns, aws.ToString(summary.ExportArn))
+	}
+	descriptions, err := p.describeExports(ctx, exportArns)
+	if err != nil {
+		return err
+	}
+	// the chain restarts from the latest full export, so the windows before it are not continued any more
+	var chainStart time.Time
+	for _, d := range descriptions {
+		if d.ExportType == types.ExportTypeIncrementalExport {
+			continue
+		}
+		if t := aws.ToTime(d.ExportTime); t.After(chainStart) {
+			chainStart = t
+		}
+	}
+	windows := make([]ExportWindow, 0, len(descriptions))
+	for _, d := range descriptions {
+		if w, ok := incrementalExportWindow(d); ok && !w.From.Before(chainStart) {
+			windows = append(windows, w)
+		}
+	}
+	return verifyExportWindows(windows)
+}
+
+func verifyExportWindows(windows []ExportWindow) error {
+	sort.Slice(windows, func(i, j int) bool { return windows[i].From.Before(windows[j].From) })
+	windowErr := &ExportWindowError{}
+	for i := 1; i < len(windows); i++ {
+		prev, curr := windows[i-1], windows[i]
+		switch {
+		case curr.From.After(prev.To):
+			windowErr.Gaps = append(windowErr.Gaps, ExportWindow{From: prev.To, To: curr.From})
+		case curr.From.Before(prev.To):
+			windowErr.Overlaps = append(windowErr.Overlaps, ExportWindow{From: curr.From, To: prev.To})
+		}
+	}
+	if len(windowErr.Gaps) > 0 || len(windowErr.Overlaps) > 0 {
+		return windowErr
+	}
+	return nil
+}
+
+func incrementalExportWindow(d *types.ExportDescription) (ExportWindow, bool) {
+	if d.ExportType != types.ExportTypeIncrementalExport || d.IncrementalExportSpecification == nil {
+		return ExportWindow{}, false
+	}
+	spec := d.IncrementalExportSpecification
+	return ExportWindow{From: aws.ToTime(spec.ExportFromTime), To: aws.ToTime(spec.ExportToTime)}, true
+}
+
+// exportCoveredUntil returns the point in time up to which the export contains table changes.
+func exportCoveredUntil(d *types.ExportDescription) time.Time {
+	if w, ok := incrementalExportWindow(d); ok {
+		return w.To
+	}
+	return aws.ToTime(d.ExportTime)
+}
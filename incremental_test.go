@@ -0,0 +1,296 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aereal/dynamodb-export-poller/internal/ddb"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/golang/mock/gomock"
+)
+
+var baseTime = time.Date(2023, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+func fullExport(exportArn string, exportTime time.Time) *types.ExportDescription {
+	return &types.ExportDescription{
+		ExportArn:    aws.String(exportArn),
+		ExportStatus: types.ExportStatusCompleted,
+		ExportType:   types.ExportTypeFullExport,
+		ExportTime:   aws.Time(exportTime),
+	}
+}
+
+func incrementalExport(exportArn string, from, to time.Time) *types.ExportDescription {
+	return &types.ExportDescription{
+		ExportArn:    aws.String(exportArn),
+		ExportStatus: types.ExportStatusCompleted,
+		ExportType:   types.ExportTypeIncrementalExport,
+		IncrementalExportSpecification: &types.IncrementalExportSpecification{
+			ExportFromTime: aws.Time(from),
+			ExportToTime:   aws.Time(to),
+		},
+	}
+}
+
+func TestPoller_PlanIncrementalExport(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	tableArn := "arn:aws:dynamodb:us-east-1:123456789012:table/my-table"
+	fullArn := tableArn + "/export/full"
+	incrArn := tableArn + "/export/incr"
+	testCases := []struct {
+		name    string
+		until   time.Time
+		onMock  func(mockClient *ddb.MockClient)
+		want    *IncrementalExportPlan
+		wantErr error
+	}{
+		{
+			"continues from full export",
+			baseTime.Add(time.Hour),
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{{ExportArn: aws.String(fullArn), ExportStatus: types.ExportStatusCompleted, ExportType: types.ExportTypeFullExport}}).Times(1)
+				describeExportOf(mockClient, fullArn, fullExport(fullArn, baseTime)).Times(1)
+			},
+			&IncrementalExportPlan{TableArn: tableArn, BaseExportArn: fullArn, Window: ExportWindow{From: baseTime, To: baseTime.Add(time.Hour)}},
+			nil,
+		},
+		{
+			"continues from latest incremental export",
+			baseTime.Add(time.Hour * 3),
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{
+					{ExportArn: aws.String(fullArn), ExportStatus: types.ExportStatusCompleted, ExportType: types.ExportTypeFullExport},
+					{ExportArn: aws.String(incrArn), ExportStatus: types.ExportStatusCompleted, ExportType: types.ExportTypeIncrementalExport},
+					{ExportArn: aws.String(tableArn + "/export/failed"), ExportStatus: types.ExportStatusFailed},
+				}).Times(1)
+				describeExportOf(mockClient, fullArn, fullExport(fullArn, baseTime)).Times(1)
+				describeExportOf(mockClient, incrArn, incrementalExport(incrArn, baseTime, baseTime.Add(time.Hour))).Times(1)
+			},
+			&IncrementalExportPlan{TableArn: tableArn, BaseExportArn: incrArn, Window: ExportWindow{From: baseTime.Add(time.Hour), To: baseTime.Add(time.Hour * 3)}},
+			nil,
+		},
+		{
+			"window is capped",
+			baseTime.Add(time.Hour * 48),
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{{ExportArn: aws.String(fullArn), ExportStatus: types.ExportStatusCompleted}}).Times(1)
+				describeExportOf(mockClient, fullArn, fullExport(fullArn, baseTime)).Times(1)
+			},
+			&IncrementalExportPlan{TableArn: tableArn, BaseExportArn: fullArn, Window: ExportWindow{From: baseTime, To: baseTime.Add(MaxIncrementalExportWindow)}},
+			nil,
+		},
+		{
+			"window too short",
+			baseTime.Add(time.Minute),
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{{ExportArn: aws.String(fullArn), ExportStatus: types.ExportStatusCompleted}}).Times(1)
+				describeExportOf(mockClient, fullArn, fullExport(fullArn, baseTime)).Times(1)
+			},
+			nil,
+			ErrIncrementalExportWindowTooShort,
+		},
+		{
+			"no completed exports",
+			baseTime,
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, nil).Times(1)
+			},
+			nil,
+			ErrNoBaseExport,
+		},
+		{
+			"export in progress",
+			baseTime,
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{{ExportArn: aws.String(incrArn), ExportStatus: types.ExportStatusInProgress}}).Times(1)
+			},
+			nil,
+			ErrExportInProgress,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := ddb.NewMockClient(ctrl)
+			tc.onMock(mockClient)
+			poller, err := NewPoller(PollerOptions{Concurrency: 2, MaxAttempts: 1}, WithClient(mockClient))
+			if err != nil {
+				t.Fatalf("NewPoller(): %s", err)
+			}
+			got, err := poller.PlanIncrementalExport(context.Background(), tableArn, tc.until)
+			assertErr(t, err, tc.wantErr)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("plan:\n\twant=%#v\n\tgot=%#v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestPoller_IncrementalExportAndWait(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tableArn := "arn:aws:dynamodb:us-east-1:123456789012:table/my-table"
+	fullArn := tableArn + "/export/full"
+	incrArn := tableArn + "/export/incr"
+	mockClient := ddb.NewMockClient(ctrl)
+	seq(
+		listExports(mockClient, []types.ExportSummary{{ExportArn: aws.String(fullArn), ExportStatus: types.ExportStatusCompleted, ExportType: types.ExportTypeFullExport}}).Times(1),
+		describeExportOf(mockClient, fullArn, fullExport(fullArn, baseTime)).Times(1),
+		mockClient.EXPECT().
+			ExportTableToPointInTime(gomock.Any(), &dynamodb.ExportTableToPointInTimeInput{
+				TableArn:   aws.String(tableArn),
+				S3Bucket:   aws.String("my-bucket"),
+				ExportType: types.ExportTypeIncrementalExport,
+				IncrementalExportSpecification: &types.IncrementalExportSpecification{
+					ExportFromTime: aws.Time(baseTime),
+					ExportToTime:   aws.Time(baseTime.Add(time.Hour)),
+					ExportViewType: types.ExportViewTypeNewImage,
+				},
+			}).
+			Return(&dynamodb.ExportTableToPointInTimeOutput{ExportDescription: &types.ExportDescription{ExportArn: aws.String(incrArn), ExportStatus: types.ExportStatusInProgress}}, nil).
+			Times(1),
+		describeExportOf(mockClient, incrArn, incrementalExport(incrArn, baseTime, baseTime.Add(time.Hour))).Times(1),
+		listExports(mockClient, []types.ExportSummary{
+			{ExportArn: aws.String(fullArn), ExportStatus: types.ExportStatusCompleted, ExportType: types.ExportTypeFullExport},
+			{ExportArn: aws.String(incrArn), ExportStatus: types.ExportStatusCompleted, ExportType: types.ExportTypeIncrementalExport},
+		}).Times(1),
+		describeExportOf(mockClient, fullArn, fullExport(fullArn, baseTime)).Times(1),
+		describeExportOf(mockClient, incrArn, incrementalExport(incrArn, baseTime, baseTime.Add(time.Hour))).Times(1),
+	)
+	poller, err := NewPoller(PollerOptions{Concurrency: 1, MaxAttempts: 1}, WithClient(mockClient))
+	if err != nil {
+		t.Fatalf("NewPoller(): %s", err)
+	}
+	got, err := poller.IncrementalExportAndWait(context.Background(), ExportRequest{
+		TableArn:       tableArn,
+		S3Bucket:       "my-bucket",
+		ExportToTime:   baseTime.Add(time.Hour),
+		ExportViewType: types.ExportViewTypeNewImage,
+	})
+	if err != nil {
+		t.Fatalf("IncrementalExportAndWait(): %s", err)
+	}
+	if got.ExportArn != incrArn {
+		t.Errorf("ExportArn:\n\twant=%s\n\tgot=%s", incrArn, got.ExportArn)
+	}
+}
+
+func TestPoller_VerifyIncrementalExports(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	tableArn := "arn:aws:dynamodb:us-east-1:123456789012:table/my-table"
+	exportArn := func(id string) string { return tableArn + "/export/" + id }
+	completed := func(id string, exportType types.ExportType) types.ExportSummary {
+		return types.ExportSummary{ExportArn: aws.String(exportArn(id)), ExportStatus: types.ExportStatusCompleted, ExportType: exportType}
+	}
+	testCases := []struct {
+		name     string
+		onMock   func(mockClient *ddb.MockClient)
+		wantGaps []ExportWindow
+	}{
+		{
+			"gap",
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{
+					completed("1", types.ExportTypeIncrementalExport),
+					completed("2", types.ExportTypeIncrementalExport),
+					{ExportArn: aws.String(exportArn("3")), ExportStatus: types.ExportStatusFailed, ExportType: types.ExportTypeIncrementalExport},
+				}).Times(1)
+				describeExportOf(mockClient, exportArn("1"), incrementalExport(exportArn("1"), baseTime, baseTime.Add(time.Hour))).Times(1)
+				describeExportOf(mockClient, exportArn("2"), incrementalExport(exportArn("2"), baseTime.Add(time.Hour*2), baseTime.Add(time.Hour*3))).Times(1)
+			},
+			[]ExportWindow{{From: baseTime.Add(time.Hour), To: baseTime.Add(time.Hour * 2)}},
+		},
+		{
+			"chain restarted from a full export",
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{
+					completed("1", types.ExportTypeIncrementalExport),
+					completed("2", types.ExportTypeFullExport),
+					completed("3", types.ExportTypeIncrementalExport),
+				}).Times(1)
+				describeExportOf(mockClient, exportArn("1"), incrementalExport(exportArn("1"), baseTime, baseTime.Add(time.Hour))).Times(1)
+				describeExportOf(mockClient, exportArn("2"), fullExport(exportArn("2"), baseTime.Add(time.Hour*5))).Times(1)
+				describeExportOf(mockClient, exportArn("3"), incrementalExport(exportArn("3"), baseTime.Add(time.Hour*5), baseTime.Add(time.Hour*6))).Times(1)
+			},
+			nil,
+		},
+		{
+			"gap in the restarted chain",
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{
+					completed("1", types.ExportTypeFullExport),
+					completed("2", types.ExportTypeIncrementalExport),
+					completed("3", types.ExportTypeIncrementalExport),
+				}).Times(1)
+				describeExportOf(mockClient, exportArn("1"), fullExport(exportArn("1"), baseTime)).Times(1)
+				describeExportOf(mockClient, exportArn("2"), incrementalExport(exportArn("2"), baseTime, baseTime.Add(time.Hour))).Times(1)
+				describeExportOf(mockClient, exportArn("3"), incrementalExport(exportArn("3"), baseTime.Add(time.Hour*2), baseTime.Add(time.Hour*3))).Times(1)
+			},
+			[]ExportWindow{{From: baseTime.Add(time.Hour), To: baseTime.Add(time.Hour * 2)}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := ddb.NewMockClient(ctrl)
+			tc.onMock(mockClient)
+			poller, err := NewPoller(PollerOptions{Concurrency: 2}, WithClient(mockClient))
+			if err != nil {
+				t.Fatalf("NewPoller(): %s", err)
+			}
+			err = poller.VerifyIncrementalExports(context.Background(), tableArn)
+			if tc.wantGaps == nil {
+				if err != nil {
+					t.Fatalf("VerifyIncrementalExports(): %s", err)
+				}
+				return
+			}
+			var windowErr *ExportWindowError
+			if !errors.As(err, &windowErr) {
+				t.Fatalf("expected ExportWindowError but got: %#v", err)
+			}
+			if !reflect.DeepEqual(windowErr.Gaps, tc.wantGaps) {
+				t.Errorf("gaps:\n\twant=%v\n\tgot=%v", tc.wantGaps, windowErr.Gaps)
+			}
+		})
+	}
+}
+
+func TestVerifyExportWindows(t *testing.T) {
+	hour := func(n int) time.Time { return baseTime.Add(time.Hour * time.Duration(n)) }
+	testCases := []struct {
+		name    string
+		windows []ExportWindow
+		want    error
+	}{
+		{"empty", nil, nil},
+		{"contiguous", []ExportWindow{{hour(1), hour(2)}, {hour(0), hour(1)}, {hour(2), hour(4)}}, nil},
+		{"gap", []ExportWindow{{hour(0), hour(1)}, {hour(2), hour(3)}}, &ExportWindowError{Gaps: []ExportWindow{{hour(1), hour(2)}}}},
+		{"overlap", []ExportWindow{{hour(0), hour(2)}, {hour(1), hour(3)}}, &ExportWindowError{Overlaps: []ExportWindow{{hour(1), hour(2)}}}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := verifyExportWindows(tc.windows)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("error:\n\twant=%v\n\tgot=%v", tc.want, got)
+			}
+		})
+	}
+}
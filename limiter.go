@@ -0,0 +1,135 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/time/rate"
+)
+
+var (
+	// ErrRequestRateMustNotBeNegative is an error that means given request rate is negative
+	ErrRequestRateMustNotBeNegative = errors.New("request rate must not be negative")
+
+	// ErrRequestBurstMustNotBeNegative is an error that means given request burst is negative
+	ErrRequestBurstMustNotBeNegative = errors.New("request burst must not be negative")
+)
+
+const (
+	// minRequestRateRatio is the lowest ratio of the configured request rate the limiter lowers the rate to
+	minRequestRateRatio = 1.0 / 16
+
+	// requestRateRecoveryRatio is the ratio of the configured request rate the limiter raises the rate by for each successful request
+	requestRateRecoveryRatio = 1.0 / 20
+)
+
+// requestLimiter is a token bucket shared by DescribeExport and ListExports requests the Poller sends.
+//
+// It halves the rate each time a request is throttled, and raises the rate gradually back to the configured rate as requests succeed.
+// The nil limiter never limits requests.
+type requestLimiter struct {
+	mux     sync.Mutex
+	limiter *rate.Limiter
+	max     rate.Limit
+	min     rate.Limit
+}
+
+func newRequestLimiter(requestRate float64, burst int) *requestLimiter {
+	if requestRate == 0 {
+		return nil
+	}
+	if burst == 0 {
+		burst = 1
+	}
+	max := rate.Limit(requestRate)
+	return &requestLimiter{
+		limiter: rate.NewLimiter(max, burst),
+		max:     max,
+		min:     max * minRequestRateRatio,
+	}
+}
+
+// wait blocks until the request is allowed or the context is done.
+func (l *requestLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	r := l.limiter.Reserve()
+	delay := r.Delay()
+	if delay == 0 {
+		return nil
+	}
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		r.Cancel()
+		return ctx.Err()
+	}
+}
+
+// observe lowers the rate if the request was throttled, or raises the rate if the request succeeded.
+func (l *requestLimiter) observe(err error, classifier ErrorClassifier) {
+	if l == nil {
+		return
+	}
+	l.mux.Lock()
+	defer l.mux.Unlock()
+	curr := l.limiter.Limit()
+	switch {
+	case err == nil:
+		if curr >= l.max {
+			return
+		}
+		next := curr + l.max*requestRateRecoveryRatio
+		if next > l.max {
+			next = l.max
+		}
+		l.limiter.SetLimit(next)
+	case classifier.Classify(err) == ErrorClassBackoffHarder:
+		next := curr / 2
+		if next < l.min {
+			next = l.min
+		}
+		l.limiter.SetLimit(next)
+		log.Debug().Float64("requestRate", float64(next)).Msg("request rate lowered since the request was throttled")
+	}
+}
+
+func (l *requestLimiter) limit() rate.Limit {
+	if l == nil {
+		return rate.Inf
+	}
+	return l.limiter.Limit()
+}
+
+func (p *Poller) describeExport(ctx context.Context, exportArn string) (*dynamodb.DescribeExportOutput, error) {
+	if err := p.limiter.wait(ctx); err != nil {
+		return nil, err
+	}
+	reqCtx, cancel := p.options.withRequestTimeout(ctx)
+	defer cancel()
+	out, err := p.clientFor(exportArn).DescribeExport(reqCtx, &dynamodb.DescribeExportInput{ExportArn: aws.String(exportArn)})
+	err = p.options.requestTimeoutError(ctx, reqCtx, "DescribeExport", err)
+	p.limiter.observe(err, p.options.errorClassifier())
+	return out, err
+}
+
+func (p *Poller) listExportsPage(ctx context.Context, input *dynamodb.ListExportsInput) (*dynamodb.ListExportsOutput, error) {
+	if err := p.limiter.wait(ctx); err != nil {
+		return nil, err
+	}
+	reqCtx, cancel := p.options.withRequestTimeout(ctx)
+	defer cancel()
+	out, err := p.clientFor(aws.ToString(input.TableArn)).ListExports(reqCtx, input)
+	err = p.options.requestTimeoutError(ctx, reqCtx, "ListExports", err)
+	p.limiter.observe(err, p.options.errorClassifier())
+	return out, err
+}
@@ -0,0 +1,100 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/aereal/dynamodb-export-poller/internal/ddb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/golang/mock/gomock"
+	"golang.org/x/time/rate"
+)
+
+func TestRequestLimiter_observe(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	throttled := &smithy.GenericAPIError{Code: "ThrottlingException", Message: "rate exceeded", Fault: smithy.FaultClient}
+	testCases := []struct {
+		name string
+		errs []error
+		want rate.Limit
+	}{
+		{"no requests", nil, 16},
+		{"succeeded at max rate", []error{nil}, 16},
+		{"throttled", []error{throttled}, 8},
+		{"throttled twice", []error{throttled, throttled}, 4},
+		{"not lower than min rate", []error{throttled, throttled, throttled, throttled, throttled, throttled}, 1},
+		{"other errors do not change the rate", []error{throttled, errors.New("oops")}, 8},
+		{"recovered gradually", []error{throttled, nil, nil}, 8 + 16.0/20*2},
+		{"recovered up to max rate", []error{throttled, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil}, 16},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			l := newRequestLimiter(16, 1)
+			for _, err := range tc.errs {
+				l.observe(err, DefaultErrorClassifier{})
+			}
+			if got := l.limit(); math.Abs(float64(got-tc.want)) > 1e-9 {
+				t.Errorf("limit: want=%v got=%v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestRequestLimiter_wait(t *testing.T) {
+	l := newRequestLimiter(1.0/3600, 1)
+	if err := l.wait(context.Background()); err != nil {
+		t.Fatalf("first wait: %s", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("second wait: want context.DeadlineExceeded but got %v", err)
+	}
+
+	var unlimited *requestLimiter
+	if err := unlimited.wait(context.Background()); err != nil {
+		t.Errorf("nil limiter: %s", err)
+	}
+	unlimited.observe(errors.New("oops"), DefaultErrorClassifier{})
+	if got := unlimited.limit(); got != rate.Inf {
+		t.Errorf("nil limiter limit: want=%v got=%v", rate.Inf, got)
+	}
+}
+
+func TestPoller_requestRate(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tableArn := "arn:aws:dynamodb:us-east-1:123456789012:table/my-table"
+	mockClient := ddb.NewMockClient(ctrl)
+	listExports(mockClient, []types.ExportSummary{}).Times(1)
+	mockClient.EXPECT().
+		DescribeExport(gomock.Any(), gomock.Any()).
+		Return(nil, &smithy.GenericAPIError{Code: "ThrottlingException", Message: "rate exceeded", Fault: smithy.FaultClient}).
+		Times(1)
+	poller, err := NewPoller(PollerOptions{Concurrency: 1, MaxAttempts: 1, RequestRate: 100, RequestBurst: 2}, WithClient(mockClient))
+	if err != nil {
+		t.Fatalf("NewPoller(): %s", err)
+	}
+	if _, err := poller.ListExports(context.Background(), tableArn); err != nil {
+		t.Fatalf("ListExports(): %s", err)
+	}
+	if got := poller.limiter.limit(); got != 100 {
+		t.Errorf("limit after ListExports: want=100 got=%v", got)
+	}
+	if _, err := poller.PollExport(context.Background(), tableArn+"/export/1"); err == nil {
+		t.Fatal("PollExport(): want error")
+	}
+	if got := poller.limiter.limit(); got != 50 {
+		t.Errorf("limit after throttled: want=50 got=%v", got)
+	}
+}
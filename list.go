@@ -0,0 +1,60 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ListExports returns summaries of all exports on the table.
+//
+// The ListExports pages are scanned according to PollerOptions.
+func (p *Poller) ListExports(ctx context.Context, tableArn string) ([]types.ExportSummary, error) {
+	if _, err := ParseTableARN(tableArn); err != nil {
+		return nil, err
+	}
+	return p.listExports(ctx, tableArn)
+}
+
+// DescribeExport returns the current description of the export.
+func (p *Poller) DescribeExport(ctx context.Context, exportArn string) (*types.ExportDescription, error) {
+	if _, err := ParseExportARN(exportArn); err != nil {
+		return nil, err
+	}
+	out, err := p.describeExport(ctx, exportArn)
+	if err != nil {
+		p.options.observer().OnAPIError(ctx, "DescribeExport", err)
+		return nil, fmt.Errorf("DescribeExport(): %w", err)
+	}
+	return out.ExportDescription, nil
+}
+
+// DescribeExportsOnTable returns descriptions of the exports on the table that match the filter.
+//
+// The exports are described concurrently up to PollerOptions.Concurrency.
+func (p *Poller) DescribeExportsOnTable(ctx context.Context, tableArn string, filter ExportFilter) ([]*types.ExportDescription, error) {
+	summaries, err := p.ListExports(ctx, tableArn)
+	if err != nil {
+		return nil, err
+	}
+	exportArns := make([]string, 0, len(summaries))
+	for _, summary := range summaries {
+		if !filter.MatchSummary(summary) {
+			continue
+		}
+		exportArns = append(exportArns, aws.ToString(summary.ExportArn))
+	}
+	descriptions, err := p.describeExports(ctx, exportArns)
+	if err != nil {
+		return nil, err
+	}
+	matched := make([]*types.ExportDescription, 0, len(descriptions))
+	for _, d := range descriptions {
+		if filter.Match(d) {
+			matched = append(matched, d)
+		}
+	}
+	return matched, nil
+}
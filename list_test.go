@@ -0,0 +1,107 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aereal/dynamodb-export-poller/internal/ddb"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/golang/mock/gomock"
+)
+
+func TestPoller_DescribeExportsOnTable(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	tableArn := "arn:aws:dynamodb:us-east-1:123456789012:table/my-table"
+	testCases := []struct {
+		name     string
+		tableArn string
+		filter   ExportFilter
+		onMock   func(mockClient *ddb.MockClient)
+		want     []*types.ExportDescription
+		wantErr  error
+	}{
+		{
+			"empty tableArn",
+			"",
+			ExportFilter{},
+			func(mockClient *ddb.MockClient) {},
+			nil,
+			ErrTableArnRequired,
+		},
+		{
+			"ok",
+			tableArn,
+			ExportFilter{},
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{
+					{ExportArn: aws.String(tableArn + "/export/1"), ExportStatus: types.ExportStatusCompleted},
+					{ExportArn: aws.String(tableArn + "/export/2"), ExportStatus: types.ExportStatusInProgress},
+				}).Times(1)
+				describeExportOf(mockClient, tableArn+"/export/1", &types.ExportDescription{ExportArn: aws.String(tableArn + "/export/1"), ExportStatus: types.ExportStatusCompleted}).Times(1)
+				describeExportOf(mockClient, tableArn+"/export/2", &types.ExportDescription{ExportArn: aws.String(tableArn + "/export/2"), ExportStatus: types.ExportStatusInProgress}).Times(1)
+			},
+			[]*types.ExportDescription{
+				{ExportArn: aws.String(tableArn + "/export/1"), ExportStatus: types.ExportStatusCompleted},
+				{ExportArn: aws.String(tableArn + "/export/2"), ExportStatus: types.ExportStatusInProgress},
+			},
+			nil,
+		},
+		{
+			"filtered",
+			tableArn,
+			ExportFilter{Statuses: []types.ExportStatus{types.ExportStatusCompleted}, StartedAfter: baseTime},
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{
+					{ExportArn: aws.String(tableArn + "/export/1"), ExportStatus: types.ExportStatusCompleted},
+					{ExportArn: aws.String(tableArn + "/export/2"), ExportStatus: types.ExportStatusCompleted},
+					{ExportArn: aws.String(tableArn + "/export/3"), ExportStatus: types.ExportStatusInProgress},
+				}).Times(1)
+				describeExportOf(mockClient, tableArn+"/export/1", &types.ExportDescription{ExportArn: aws.String(tableArn + "/export/1"), ExportStatus: types.ExportStatusCompleted, StartTime: aws.Time(baseTime)}).Times(1)
+				describeExportOf(mockClient, tableArn+"/export/2", &types.ExportDescription{ExportArn: aws.String(tableArn + "/export/2"), ExportStatus: types.ExportStatusCompleted, StartTime: aws.Time(baseTime.Add(-time.Hour))}).Times(1)
+			},
+			[]*types.ExportDescription{
+				{ExportArn: aws.String(tableArn + "/export/1"), ExportStatus: types.ExportStatusCompleted, StartTime: aws.Time(baseTime)},
+			},
+			nil,
+		},
+		{
+			"DescribeExport error",
+			tableArn,
+			ExportFilter{},
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{{ExportArn: aws.String(tableArn + "/export/1")}}).Times(1)
+				mockClient.EXPECT().
+					DescribeExport(gomock.Any(), gomock.Any()).
+					Return(nil, &smithy.GenericAPIError{Code: "oops", Message: "oops", Fault: smithy.FaultClient}).
+					Times(1)
+			},
+			nil,
+			errors.New("DescribeExport(" + tableArn + "/export/1): api error oops: oops"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := ddb.NewMockClient(ctrl)
+			tc.onMock(mockClient)
+			poller, err := NewPoller(PollerOptions{Concurrency: 2}, WithClient(mockClient))
+			if err != nil {
+				t.Fatalf("NewPoller(): %s", err)
+			}
+			got, err := poller.DescribeExportsOnTable(context.Background(), tc.tableArn, tc.filter)
+			assertErr(t, err, tc.wantErr)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("descriptions:\n\twant=%#v\n\tgot=%#v", tc.want, got)
+			}
+		})
+	}
+}
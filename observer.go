@@ -0,0 +1,56 @@
+package ddbexportpoller
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// PollerObserver is notified of the events that the Poller sees while polling export jobs.
+//
+// The methods may be called concurrently from multiple goroutines.
+type PollerObserver interface {
+	// OnPollStart is called before each export job status check request is sent.
+	//
+	// The attempt starts from 1.
+	OnPollStart(ctx context.Context, exportArn string, attempt int)
+
+	// OnStatusObserved is called each time the Poller receives the export job description.
+	OnStatusObserved(ctx context.Context, exportArn string, description *types.ExportDescription)
+
+	// OnExportCompleted is called when the export job has been completed.
+	OnExportCompleted(ctx context.Context, result *ExportResult)
+
+	// OnExportFailed is called when the export job has finished with FAILED status.
+	OnExportFailed(ctx context.Context, result *ExportResult, err *ExportFailedError)
+
+	// OnGiveUp is called when the Poller stops polling the export job before it finishes.
+	//
+	// e.g. the max attempts exceeded, the timeout reached or an unrecoverable error occurred.
+	// The result is marked as Abandoned if the Poller stopped polling because PollerOptions.Quorum was reached.
+	OnGiveUp(ctx context.Context, result *ExportResult, err error)
+
+	// OnAPIError is called when DynamoDB API request fails.
+	OnAPIError(ctx context.Context, operation string, err error)
+}
+
+// NopObserver is a PollerObserver that does nothing.
+//
+// Embed it into your own observer to implement only the methods you are interested in.
+type NopObserver struct{}
+
+var _ PollerObserver = NopObserver{}
+
+func (NopObserver) OnPollStart(ctx context.Context, exportArn string, attempt int) {}
+
+func (NopObserver) OnStatusObserved(ctx context.Context, exportArn string, description *types.ExportDescription) {
+}
+
+func (NopObserver) OnExportCompleted(ctx context.Context, result *ExportResult) {}
+
+func (NopObserver) OnExportFailed(ctx context.Context, result *ExportResult, err *ExportFailedError) {
+}
+
+func (NopObserver) OnGiveUp(ctx context.Context, result *ExportResult, err error) {}
+
+func (NopObserver) OnAPIError(ctx context.Context, operation string, err error) {}
@@ -0,0 +1,140 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/aereal/dynamodb-export-poller/internal/ddb"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/golang/mock/gomock"
+)
+
+func TestPollerObserver(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	exportArn := "arn:aws:dynamodb:us-east-1:123456789012:table/my-table/export/1234-5678"
+	testCases := []struct {
+		name        string
+		maxAttempts int
+		onMock      func(mockClient *ddb.MockClient)
+		want        []string
+	}{
+		{
+			"completed",
+			2,
+			func(mockClient *ddb.MockClient) {
+				seq(
+					describeExport(mockClient, &types.ExportDescription{ExportStatus: types.ExportStatusInProgress}).Times(1),
+					describeExport(mockClient, &types.ExportDescription{ExportStatus: types.ExportStatusCompleted}).Times(1),
+				)
+			},
+			[]string{
+				"OnPollStart attempt=1",
+				"OnStatusObserved status=IN_PROGRESS",
+				"OnPollStart attempt=2",
+				"OnStatusObserved status=COMPLETED",
+				"OnExportCompleted status=COMPLETED polls=2",
+			},
+		},
+		{
+			"failed",
+			2,
+			func(mockClient *ddb.MockClient) {
+				describeExport(mockClient, &types.ExportDescription{ExportStatus: types.ExportStatusFailed, FailureCode: aws.String("S3AccessDenied")}).Times(1)
+			},
+			[]string{
+				"OnPollStart attempt=1",
+				"OnStatusObserved status=FAILED",
+				"OnExportFailed code=S3AccessDenied",
+			},
+		},
+		{
+			"give up",
+			1,
+			func(mockClient *ddb.MockClient) {
+				describeExport(mockClient, &types.ExportDescription{ExportStatus: types.ExportStatusInProgress}).Times(1)
+			},
+			[]string{
+				"OnPollStart attempt=1",
+				"OnStatusObserved status=IN_PROGRESS",
+				"OnGiveUp status=IN_PROGRESS err=export has not been finished",
+			},
+		},
+		{
+			"API error",
+			1,
+			func(mockClient *ddb.MockClient) {
+				mockClient.EXPECT().
+					DescribeExport(gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("oops")).
+					Times(1)
+			},
+			[]string{
+				"OnPollStart attempt=1",
+				"OnAPIError operation=DescribeExport err=oops",
+				"OnGiveUp status= err=oops",
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := ddb.NewMockClient(ctrl)
+			tc.onMock(mockClient)
+			observer := &recordingObserver{}
+			poller, err := NewPoller(PollerOptions{Concurrency: 1, MaxAttempts: tc.maxAttempts, Observer: observer}, WithClient(mockClient))
+			if err != nil {
+				t.Fatalf("NewPoller(): %s", err)
+			}
+			_, _ = poller.PollExport(context.Background(), exportArn)
+			if !reflect.DeepEqual(observer.events, tc.want) {
+				t.Errorf("events:\n\twant=%#v\n\tgot=%#v", tc.want, observer.events)
+			}
+		})
+	}
+}
+
+type recordingObserver struct {
+	mu     sync.Mutex
+	events []string
+}
+
+var _ PollerObserver = &recordingObserver{}
+
+func (o *recordingObserver) record(format string, args ...interface{}) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, fmt.Sprintf(format, args...))
+}
+
+func (o *recordingObserver) OnPollStart(ctx context.Context, exportArn string, attempt int) {
+	o.record("OnPollStart attempt=%d", attempt)
+}
+
+func (o *recordingObserver) OnStatusObserved(ctx context.Context, exportArn string, description *types.ExportDescription) {
+	o.record("OnStatusObserved status=%s", description.ExportStatus)
+}
+
+func (o *recordingObserver) OnExportCompleted(ctx context.Context, result *ExportResult) {
+	o.record("OnExportCompleted status=%s polls=%d", result.Status, result.Polls)
+}
+
+func (o *recordingObserver) OnExportFailed(ctx context.Context, result *ExportResult, err *ExportFailedError) {
+	o.record("OnExportFailed code=%s", err.FailureCode)
+}
+
+func (o *recordingObserver) OnGiveUp(ctx context.Context, result *ExportResult, err error) {
+	o.record("OnGiveUp status=%s err=%s", result.Status, err)
+}
+
+func (o *recordingObserver) OnAPIError(ctx context.Context, operation string, err error) {
+	o.record("OnAPIError operation=%s err=%s", operation, err)
+}
@@ -0,0 +1,58 @@
+package ddbexportpoller
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+)
+
+// Option configures how NewPoller builds the Poller.
+type Option func(c *pollerConfig)
+
+type pollerConfig struct {
+	ctx       context.Context
+	awsConfig *aws.Config
+	client    Client
+
+	regionalClients map[clientKey]Client
+}
+
+// WithContext is an option to give the context used to load the default AWS configuration.
+//
+// The default is context.Background().
+func WithContext(ctx context.Context) Option {
+	return func(c *pollerConfig) {
+		c.ctx = ctx
+	}
+}
+
+// WithAWSConfig is an option to give the AWS configuration used to build the DynamoDB client.
+//
+// The default configuration is loaded from the environment if it is not given.
+func WithAWSConfig(cfg aws.Config) Option {
+	return func(c *pollerConfig) {
+		c.awsConfig = &cfg
+	}
+}
+
+// WithClient is an option to give the DynamoDB client used by the Poller.
+//
+// The client is used for the tables and the exports outside of PollerOptions.AccountRegions.
+// WithAWSConfig is still used to build the clients for the AccountRegions that no client is given for by WithRegionalClient.
+func WithClient(client Client) Option {
+	return func(c *pollerConfig) {
+		c.client = client
+	}
+}
+
+// WithRegionalClient is an option to give the DynamoDB client used for the tables and the exports in the account and the region.
+//
+// Empty accountID means any account in the region. The Poller does not build the client for the AccountRegion that the client is given for.
+func WithRegionalClient(accountID, region string, client Client) Option {
+	return func(c *pollerConfig) {
+		if c.regionalClients == nil {
+			c.regionalClients = map[clientKey]Client{}
+		}
+		c.regionalClients[clientKey{accountID: accountID, region: region}] = client
+	}
+}
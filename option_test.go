@@ -0,0 +1,60 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aereal/dynamodb-export-poller/internal/ddb"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/golang/mock/gomock"
+)
+
+func TestNewPoller_options(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockClient := ddb.NewMockClient(ctrl)
+
+	testCases := []struct {
+		name       string
+		opts       []Option
+		wantClient func(t *testing.T, client Client)
+	}{
+		{
+			"WithClient",
+			[]Option{WithClient(mockClient)},
+			func(t *testing.T, client Client) {
+				if client != mockClient {
+					t.Errorf("client:\n\twant=%#v\n\tgot=%#v", mockClient, client)
+				}
+			},
+		},
+		{
+			"WithClient takes precedence over WithAWSConfig",
+			[]Option{WithAWSConfig(aws.Config{Region: "us-east-1"}), WithClient(mockClient)},
+			func(t *testing.T, client Client) {
+				if client != mockClient {
+					t.Errorf("client:\n\twant=%#v\n\tgot=%#v", mockClient, client)
+				}
+			},
+		},
+		{
+			"WithAWSConfig",
+			[]Option{WithContext(context.Background()), WithAWSConfig(aws.Config{Region: "ap-northeast-1"})},
+			func(t *testing.T, client Client) {
+				if _, ok := client.(*dynamodb.Client); !ok {
+					t.Errorf("client must be *dynamodb.Client but got %T", client)
+				}
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			poller, err := NewPoller(PollerOptions{Concurrency: 1}, tc.opts...)
+			if err != nil {
+				t.Fatalf("NewPoller(): %s", err)
+			}
+			tc.wantClient(t, poller.client)
+		})
+	}
+}
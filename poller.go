@@ -6,15 +6,12 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/aereal/dynamodb-export-poller/internal/ddb"
-	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
-	"github.com/aws/smithy-go"
 	"github.com/hashicorp/go-multierror"
 	"github.com/rs/zerolog/log"
-	"github.com/shogo82148/go-retry"
 	"golang.org/x/sync/semaphore"
 )
 
@@ -28,12 +25,35 @@ var (
 	// ErrConcurrencyMustBePositive is an error that means given concurrency is too small
 	ErrConcurrencyMustBePositive = errors.New("concurrency must greater than 0")
 
-	// ErrExportHasNotBeenFinished is an error that ongoing export jobs have not been finished until the deadline.
+	// ErrListExportsPageSizeOutOfRange is an error that means given page size of ListExports is out of range
+	ErrListExportsPageSizeOutOfRange = fmt.Errorf("list exports page size must be between 0 and %d", maxListExportsPageSize)
+
+	// ErrMaxListExportsPagesMustNotBeNegative is an error that means given max pages of ListExports is negative
+	ErrMaxListExportsPagesMustNotBeNegative = errors.New("max list exports pages must not be negative")
+
+	// ErrMinExportsMustNotBeNegative is an error that means given min exports is negative
+	ErrMinExportsMustNotBeNegative = errors.New("min exports must not be negative")
+
+	// ErrMinExportsRequiresNewExportsFilter is an error that means min exports is given without the filter that tells new export jobs from old ones
+	ErrMinExportsRequiresNewExportsFilter = errors.New("min exports requires the filter of started after, export time after or client token")
+
+	// ErrExportHasNotBeenFinished is an error that ongoing export jobs have not been finished within MaxAttempts.
 	//
-	// The error is not returned if MaxAttempts and Timeout are zero.
+	// The error is not returned if MaxAttempts is zero. TimeoutError is returned instead if the timeout is reached.
 	ErrExportHasNotBeenFinished = errors.New("export has not been finished")
 )
 
+// ExportFailedError is an error that means the export job has finished with FAILED status.
+type ExportFailedError struct {
+	ExportArn      string
+	FailureCode    string
+	FailureMessage string
+}
+
+func (e *ExportFailedError) Error() string {
+	return fmt.Sprintf("export %s failed: %s: %s", e.ExportArn, e.FailureCode, e.FailureMessage)
+}
+
 // PollerOptions is a set of Poller's options
 type PollerOptions struct {
 	// InitialDelay is used for first interval
@@ -42,26 +62,151 @@ type PollerOptions struct {
 	// MaxDelay is maximum interval for each requests
 	MaxDelay time.Duration
 
+	// Backoff decides the intervals between export job status check requests and the extra delays after throttled requests.
+	//
+	// ExponentialBackoff from InitialDelay up to MaxDelay is used if it is nil.
+	Backoff BackoffStrategy
+
+	// AdaptiveScheduling makes the Poller predict when each export job completes from the table size and the durations of the past export jobs on the table.
+	//
+	// The Poller waits half of the time left until the predicted completion between InitialDelay and AdaptiveMaxDelay, and uses Backoff once the predicted time has passed.
+	AdaptiveScheduling bool
+
+	// AdaptiveMaxDelay is the longest interval of AdaptiveScheduling. Zero means 10 minutes.
+	AdaptiveMaxDelay time.Duration
+
 	// MaxAttempts is a number to send export job status check requests
 	MaxAttempts int
 
 	// Concurrency means max number of requests at the same time
 	Concurrency int64
 
-	// Timeout is used for all export job status check requests. No requests are sent over this timeout.
+	// Timeout limits each call of PollExport, PollExportsOnTable, PollExportsOnTables, Poll, Watch, ExportAndWait and IncrementalExportAndWait as a whole,
+	// including discovering the tables, starting and planning the export job, listing the export jobs and waiting for MinExports export jobs to appear. No requests are sent over this timeout.
+	//
+	// TimeoutError is returned if export jobs are still in progress at the timeout.
 	Timeout time.Duration
+
+	// ExportTimeout is the time limit to wait for each export job. Zero means no limit.
+	ExportTimeout time.Duration
+
+	// RequestTimeout is the time limit of each DynamoDB API request. Zero means no limit.
+	//
+	// The timed out request fails with RequestTimeoutError, and DescribeExport and ListExports requests are retried.
+	RequestTimeout time.Duration
+
+	// ListExportsPageSize is a maximum number of exports returned by each ListExports request.
+	//
+	// Zero means the service default.
+	ListExportsPageSize int32
+
+	// MaxListExportsPages is a maximum number of ListExports pages scanned to find exports on the table.
+	//
+	// Zero means all pages are scanned.
+	MaxListExportsPages int
+
+	// Observer is notified of the events that the Poller sees.
+	Observer PollerObserver
+
+	// Filter selects the in-progress export jobs to wait on among those found on the tables.
+	//
+	// Statuses of the filter is ignored because only in-progress export jobs are waited on, and export jobs given by their ARNs are not filtered.
+	Filter ExportFilter
+
+	// MinExports is the number of export jobs matching Filter that must appear on each table before polling begins.
+	//
+	// If it is positive, the Poller lists exports on the table repeatedly until MinExports export jobs of any status appear, and then waits for them to finish.
+	// Filter must have StartedAfter, ExportTimeAfter or ClientToken so that export jobs finished before are not counted.
+	// Zero means the Poller waits on only the in-progress export jobs found at first.
+	MinExports int
+
+	// AppearanceTimeout is the time limit to wait for MinExports export jobs to appear. Zero means no limit.
+	AppearanceTimeout time.Duration
+
+	// Quorum is the number of export jobs that must complete before PollExportsOnTable and Poll return.
+	//
+	// Once the quorum is reached, the Poller stops polling the rest of the export jobs and marks their results as Abandoned.
+	// Errors of the abandoned export jobs are not returned, but errors of the export jobs that finished before, such as ExportFailedError, are.
+	// QuorumAll (zero) means the Poller waits for all of the export jobs, and QuorumAny means it returns as soon as any export job completes.
+	// If the quorum is greater than the number of the export jobs found, the Poller waits for all of them.
+	Quorum int
+
+	// ErrorClassifier decides whether the Poller retries the failed request. DefaultErrorClassifier is used if it is nil.
+	ErrorClassifier ErrorClassifier
+
+	// RequestRate is the maximum number of DescribeExport and ListExports requests per second the Poller sends in total.
+	//
+	// The Poller lowers the rate while the requests are throttled and raises it back as they succeed. Zero means no limit.
+	RequestRate float64
+
+	// RequestBurst is the number of requests the Poller can send at once within RequestRate. Zero means 1.
+	RequestBurst int
+
+	// AccountRegions are the accounts and the regions where the tables and the exports reside.
+	//
+	// Empty means the Poller accesses only the account and the region of the AWS configuration.
+	AccountRegions []AccountRegion
 }
 
+const maxListExportsPageSize int32 = 25
+
 func (o PollerOptions) validate() error {
 	var err error
 	if o.Concurrency <= 0 {
 		err = multierror.Append(err, ErrConcurrencyMustBePositive)
 	}
+	if o.ListExportsPageSize < 0 || o.ListExportsPageSize > maxListExportsPageSize {
+		err = multierror.Append(err, ErrListExportsPageSizeOutOfRange)
+	}
+	if o.MaxListExportsPages < 0 {
+		err = multierror.Append(err, ErrMaxListExportsPagesMustNotBeNegative)
+	}
+	if o.MinExports < 0 {
+		err = multierror.Append(err, ErrMinExportsMustNotBeNegative)
+	}
+	if o.MinExports > 0 && o.Filter.StartedAfter.IsZero() && o.Filter.ExportTimeAfter.IsZero() && o.Filter.ClientToken == "" {
+		err = multierror.Append(err, ErrMinExportsRequiresNewExportsFilter)
+	}
+	if o.RequestRate < 0 {
+		err = multierror.Append(err, ErrRequestRateMustNotBeNegative)
+	}
+	if o.RequestBurst < 0 {
+		err = multierror.Append(err, ErrRequestBurstMustNotBeNegative)
+	}
+	if o.Quorum < 0 {
+		err = multierror.Append(err, ErrQuorumMustNotBeNegative)
+	}
+	for _, ar := range o.AccountRegions {
+		if arErr := ar.validate(); arErr != nil {
+			err = multierror.Append(err, arErr)
+		}
+	}
 	return err
 }
 
 var noop = func() {}
 
+func (o PollerOptions) observer() PollerObserver {
+	if o.Observer == nil {
+		return NopObserver{}
+	}
+	return o.Observer
+}
+
+func (o PollerOptions) backoff() BackoffStrategy {
+	if o.Backoff == nil {
+		return ExponentialBackoff{InitialDelay: o.InitialDelay, MaxDelay: o.MaxDelay}
+	}
+	return o.Backoff
+}
+
+func (o PollerOptions) errorClassifier() ErrorClassifier {
+	if o.ErrorClassifier == nil {
+		return DefaultErrorClassifier{}
+	}
+	return o.ErrorClassifier
+}
+
 func (o PollerOptions) withTimeout(parent context.Context) (context.Context, func()) {
 	if o.Timeout == 0 {
 		return parent, noop
@@ -72,24 +217,53 @@ func (o PollerOptions) withTimeout(parent context.Context) (context.Context, fun
 // NewPoller creates new Poller.
 //
 // An error may be returned if you passed invalid options.
-func NewPoller(options PollerOptions) (*Poller, error) {
+func NewPoller(options PollerOptions, opts ...Option) (*Poller, error) {
 	if err := options.validate(); err != nil {
 		return nil, err
 	}
 
-	ctx := context.Background()
-	cfg, err := config.LoadDefaultConfig(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("LoadDefaultConfig(): %w", err)
+	pc := &pollerConfig{ctx: context.Background()}
+	for _, opt := range opts {
+		opt(pc)
+	}
+	poller := &Poller{options: options, client: pc.client, clients: map[clientKey]Client{}, limiter: newRequestLimiter(options.RequestRate, options.RequestBurst)}
+	for key, client := range pc.regionalClients {
+		poller.clients[key] = client
+	}
+	needsConfig := poller.client == nil
+	for _, ar := range options.AccountRegions {
+		if _, ok := poller.clients[ar.key()]; !ok {
+			needsConfig = true
+		}
+	}
+	if !needsConfig {
+		return poller, nil
+	}
+	if pc.awsConfig == nil {
+		cfg, err := config.LoadDefaultConfig(pc.ctx)
+		if err != nil {
+			return nil, fmt.Errorf("LoadDefaultConfig(): %w", err)
+		}
+		pc.awsConfig = &cfg
+	}
+	if poller.client == nil {
+		poller.client = dynamodb.NewFromConfig(*pc.awsConfig)
+	}
+	for _, ar := range options.AccountRegions {
+		if _, ok := poller.clients[ar.key()]; !ok {
+			poller.clients[ar.key()] = ar.newClient(*pc.awsConfig)
+		}
 	}
-	poller := &Poller{options: options}
-	poller.client = dynamodb.NewFromConfig(cfg)
 	return poller, nil
 }
 
 type Poller struct {
 	options PollerOptions
-	client  ddb.Client
+	client  Client
+	clients map[clientKey]Client
+	limiter *requestLimiter
+
+	predictions predictions
 }
 
 const semaphoreWorkerAmount int64 = 1
@@ -97,76 +271,294 @@ const semaphoreWorkerAmount int64 = 1
 // PollExport polls ongoing export job status changes.
 //
 // You can configure polling behaviors through PollerOptions.
-func (p *Poller) PollExport(ctx context.Context, exportArn string) error {
-	if !arn.IsARN(exportArn) {
-		return ErrExportArnRequired
+//
+// The result is returned even if an error occurred as long as the Poller started polling.
+func (p *Poller) PollExport(ctx context.Context, exportArn string) (*ExportResult, error) {
+	if _, err := ParseExportARN(exportArn); err != nil {
+		return nil, err
 	}
-	return p.pollExportWithRetries(ctx, exportArn)
+	ctx, cancel := p.options.withTimeout(ctx)
+	defer cancel()
+	return p.pollExportWithRetries(ctx, newExportTracker(exportArn))
 }
 
 // PollExportsOnTable polls ongoing export job status changes.
 //
 // You can configure polling behaviors through PollerOptions.
-func (p *Poller) PollExportsOnTable(ctx context.Context, tableArn string) error {
-	if !arn.IsARN(tableArn) {
-		return ErrTableArnRequired
+//
+// The results contain every in-progress export job found on the table even if an error occurred.
+// If PollerOptions.Quorum is reached, it returns without waiting for the rest of the export jobs and their results are marked as Abandoned.
+func (p *Poller) PollExportsOnTable(ctx context.Context, tableArn string) ([]*ExportResult, error) {
+	if _, err := ParseTableARN(tableArn); err != nil {
+		return nil, err
 	}
 
-	out, err := p.client.ListExports(ctx, &dynamodb.ListExportsInput{TableArn: &tableArn})
+	ctx, cancel := p.options.withTimeout(ctx)
+	defer cancel()
+	exportArns, err := p.exportArnsToWait(ctx, tableArn)
 	if err != nil {
-		return fmt.Errorf("ListExports(): %w", err)
+		return nil, err
 	}
+	return p.pollExports(ctx, exportArns, p.options.Quorum, newExportTracker)
+}
 
+func (p *Poller) pollExports(ctx context.Context, exportArns []string, want int, newTracker func(exportArn string) *exportTracker) ([]*ExportResult, error) {
+	q, ctx := newQuorum(ctx, want, len(exportArns))
+	defer q.cancel()
 	sem := semaphore.NewWeighted(p.options.Concurrency)
-	ctx, cancel := p.options.withTimeout(ctx)
-	defer cancel()
 	meg := &multierror.Group{}
-	for _, summary := range out.ExportSummaries {
-		if summary.ExportStatus != types.ExportStatusInProgress {
+	results := make([]*ExportResult, len(exportArns))
+	errs := make([]error, len(exportArns))
+	trackers := make([]*exportTracker, len(exportArns))
+	for i, exportArn := range exportArns {
+		trackers[i] = newTracker(exportArn)
+		trackers[i].abandoned = q.abandoned
+	}
+	var acquireErr error
+	for i, tracker := range trackers {
+		i, tracker := i, tracker
+		err := acquireErr
+		if err == nil {
+			err = sem.Acquire(ctx, semaphoreWorkerAmount)
+		}
+		if q.isReached() {
+			// the export job is not polled at all since the quorum has been reached while waiting for the semaphore
+			if err == nil {
+				sem.Release(semaphoreWorkerAmount)
+			}
+			results[i] = tracker.result()
+			results[i].Abandoned = true
+			continue
+		}
+		if err != nil {
+			// the export job is not polled at all since the context is done while waiting for the semaphore
+			acquireErr = err
+			if errors.Is(err, context.DeadlineExceeded) {
+				err = newTimeoutError(p.options.Timeout, tracker.result())
+			}
+			results[i] = p.settleExport(ctx, tracker, err)
+			errs[i] = err
+			continue
+		}
+		meg.Go(func() error {
+			defer sem.Release(semaphoreWorkerAmount)
+			result, err := p.pollExportWithRetries(ctx, tracker)
+			results[i] = result
+			if err == nil {
+				q.complete()
+				return nil
+			}
+			if result.Abandoned {
+				return nil
+			}
+			errs[i] = err
+			return err
+		})
+	}
+	_ = meg.Wait()
+	// errors of the abandoned export jobs are not recorded
+	return results, combineExportErrors(errs)
+}
+
+// combineExportErrors combines the errors of the export jobs in order, merging TimeoutErrors of the same timeout into one.
+func combineExportErrors(errs []error) error {
+	var merr *multierror.Error
+	timeoutErrs := map[time.Duration]*TimeoutError{}
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		var timeoutErr *TimeoutError
+		if errors.As(err, &timeoutErr) {
+			if merged, ok := timeoutErrs[timeoutErr.Timeout]; ok {
+				merged.Exports = append(merged.Exports, timeoutErr.Exports...)
+				continue
+			}
+			merged := &TimeoutError{Timeout: timeoutErr.Timeout, Exports: append([]TimedOutExport(nil), timeoutErr.Exports...)}
+			timeoutErrs[timeoutErr.Timeout] = merged
+			err = merged
+		}
+		merr = multierror.Append(merr, err)
+	}
+	return merr.ErrorOrNil()
+}
+
+// exportArnsToWait returns ARNs of the export jobs on the table to wait on.
+//
+// They are in-progress export jobs, or the export jobs that appeared if PollerOptions.MinExports is set.
+func (p *Poller) exportArnsToWait(ctx context.Context, tableArn string) ([]string, error) {
+	if p.options.MinExports > 0 {
+		return p.awaitExports(ctx, tableArn)
+	}
+	return p.matchedExportArns(ctx, tableArn, true)
+}
+
+// matchedExportArns returns ARNs of the export jobs on the table that match PollerOptions.Filter except for its Statuses.
+func (p *Poller) matchedExportArns(ctx context.Context, tableArn string, inProgressOnly bool) ([]string, error) {
+	summaries, err := p.listExports(ctx, tableArn)
+	if err != nil {
+		return nil, err
+	}
+	filter := p.options.Filter
+	filter.Statuses = nil
+	exportArns := make([]string, 0, len(summaries))
+	for _, summary := range summaries {
+		if inProgressOnly && summary.ExportStatus != types.ExportStatusInProgress {
+			continue
+		}
+		if !filter.MatchSummary(summary) {
 			continue
 		}
-		exportArn := summary.ExportArn
+		exportArns = append(exportArns, aws.ToString(summary.ExportArn))
+	}
+	if !filter.needsDescription() {
+		return exportArns, nil
+	}
+	descriptions, err := p.describeExports(ctx, exportArns)
+	if err != nil {
+		return nil, err
+	}
+	matched := make([]string, 0, len(exportArns))
+	for i, d := range descriptions {
+		if filter.Match(d) {
+			matched = append(matched, exportArns[i])
+		}
+	}
+	log.Debug().Str("tableArn", tableArn).Int("found", len(descriptions)).Int("matched", len(matched)).Msg("exports filtered")
+	return matched, nil
+}
+
+func (p *Poller) listExports(ctx context.Context, tableArn string) ([]types.ExportSummary, error) {
+	input := &dynamodb.ListExportsInput{TableArn: &tableArn}
+	if p.options.ListExportsPageSize > 0 {
+		input.MaxResults = aws.Int32(p.options.ListExportsPageSize)
+	}
+	var summaries []types.ExportSummary
+	for pages := 0; ; pages++ {
+		if p.options.MaxListExportsPages > 0 && pages >= p.options.MaxListExportsPages {
+			log.Warn().Str("tableArn", tableArn).Int("pages", pages).Msg("reached to max list exports pages; remaining exports are ignored")
+			break
+		}
+		var out *dynamodb.ListExportsOutput
+		err := p.retryRequest(ctx, "ListExports", func() (err error) {
+			out, err = p.listExportsPage(ctx, input)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("ListExports(): %w", err)
+		}
+		summaries = append(summaries, out.ExportSummaries...)
+		if out.NextToken == nil || *out.NextToken == "" {
+			break
+		}
+		input.NextToken = out.NextToken
+	}
+	return summaries, nil
+}
+
+func (p *Poller) describeExports(ctx context.Context, exportArns []string) ([]*types.ExportDescription, error) {
+	sem := semaphore.NewWeighted(p.options.Concurrency)
+	meg := &multierror.Group{}
+	descriptions := make([]*types.ExportDescription, len(exportArns))
+	for i, exportArn := range exportArns {
+		i, exportArn := i, exportArn
 		if err := sem.Acquire(ctx, semaphoreWorkerAmount); err != nil {
-			log.Error().Err(err).Str("exportArn", *exportArn).Msg("failed to acquire semaphore")
-			return nil
+			_ = meg.Wait()
+			return nil, err
 		}
 		meg.Go(func() error {
 			defer sem.Release(semaphoreWorkerAmount)
-			return p.pollExportWithRetries(ctx, *exportArn)
+			var out *dynamodb.DescribeExportOutput
+			err := p.retryRequest(ctx, "DescribeExport", func() (err error) {
+				out, err = p.describeExport(ctx, exportArn)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("DescribeExport(%s): %w", exportArn, err)
+			}
+			descriptions[i] = out.ExportDescription
+			return nil
 		})
 	}
 	if err := meg.Wait().ErrorOrNil(); err != nil {
-		return err
+		return nil, err
 	}
+	return descriptions, nil
+}
 
-	return nil
+// retryRequest sends the request by f until it succeeds, retrying on the errors classified as retryable or backoff-harder as polling export jobs does.
+func (p *Poller) retryRequest(ctx context.Context, operation string, f func() error) error {
+	var throttles int
+	return retryWithBackoff(ctx, p.options.backoff(), p.options.MaxAttempts, func() error {
+		err := f()
+		if err == nil {
+			return nil
+		}
+		p.options.observer().OnAPIError(ctx, operation, err)
+		return p.classifyError(ctx, err, &throttles)
+	})
 }
 
-func (p *Poller) pollExportWithRetries(ctx context.Context, exportArn string) error {
-	policy := &retry.Policy{
-		MinDelay: p.options.InitialDelay,
-		MaxDelay: p.options.MaxDelay,
-		MaxCount: p.options.MaxAttempts,
+func (p *Poller) pollExportWithRetries(ctx context.Context, tracker *exportTracker) (*ExportResult, error) {
+	strategy := p.options.backoff()
+	if p.options.AdaptiveScheduling {
+		strategy = p.newAdaptiveBackoff(ctx, tracker)
 	}
-	return policy.Do(ctx, func() error { return p.pollExport(ctx, exportArn) })
+	exportCtx, cancel := p.options.withExportTimeout(ctx)
+	defer cancel()
+	err := retryWithBackoff(exportCtx, strategy, p.options.MaxAttempts, func() error { return p.pollExport(exportCtx, tracker) })
+	if err != nil && exportCtx.Err() == context.DeadlineExceeded && errors.Is(err, context.DeadlineExceeded) {
+		timeout := p.options.Timeout
+		if ctx.Err() == nil {
+			timeout = p.options.ExportTimeout
+		}
+		err = newTimeoutError(timeout, tracker.result())
+	}
+	return p.settleExport(ctx, tracker, err), err
 }
 
-func (p *Poller) pollExport(ctx context.Context, exportArn string) error {
+// settleExport notifies the observer of the end of polling the export job and returns its result.
+func (p *Poller) settleExport(ctx context.Context, tracker *exportTracker, err error) *ExportResult {
+	result := tracker.settle(err)
+	observer := p.options.observer()
+	var failedErr *ExportFailedError
+	switch {
+	case err == nil:
+		observer.OnExportCompleted(ctx, result)
+	case errors.As(err, &failedErr):
+		observer.OnExportFailed(ctx, result, failedErr)
+	default:
+		observer.OnGiveUp(ctx, result, err)
+	}
+	return result
+}
+
+func (p *Poller) pollExport(ctx context.Context, tracker *exportTracker) error {
+	exportArn := tracker.exportArn
 	l := log.With().Str("exportArn", exportArn).Logger()
 	l.Debug().Msg("start describe export")
-	out, err := p.client.DescribeExport(ctx, &dynamodb.DescribeExportInput{ExportArn: &exportArn})
+	tracker.polls++
+	observer := p.options.observer()
+	observer.OnPollStart(ctx, exportArn, tracker.polls)
+	out, err := p.describeExport(ctx, exportArn)
 	if err != nil {
-		var apiErr smithy.APIError
-		if errors.As(err, &apiErr) {
-			if apiErr.ErrorFault() == smithy.FaultClient {
-				return retry.MarkPermanent(err)
-			}
-		}
-		return err
+		observer.OnAPIError(ctx, "DescribeExport", err)
+		return p.classifyError(ctx, err, &tracker.throttles)
 	}
-	if out.ExportDescription.ExportStatus == types.ExportStatusInProgress {
+	tracker.throttles = 0
+	tracker.observe(out.ExportDescription)
+	observer.OnStatusObserved(ctx, exportArn, out.ExportDescription)
+	switch out.ExportDescription.ExportStatus {
+	case types.ExportStatusInProgress:
 		l.Debug().Msg("export is still in progress")
 		return ErrExportHasNotBeenFinished
+	case types.ExportStatusFailed:
+		l.Debug().Msg("export failed")
+		return markPermanent(&ExportFailedError{
+			ExportArn:      exportArn,
+			FailureCode:    aws.ToString(out.ExportDescription.FailureCode),
+			FailureMessage: aws.ToString(out.ExportDescription.FailureMessage),
+		})
 	}
 	l.Debug().Msg("export finishes")
 	return nil
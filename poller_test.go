@@ -3,8 +3,11 @@ package ddbexportpoller
 import (
 	"context"
 	"errors"
+	"fmt"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/aereal/dynamodb-export-poller/internal/ddb"
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -77,22 +80,43 @@ func TestPoller_PollExport(t *testing.T) {
 			},
 			ErrExportHasNotBeenFinished,
 		},
+		{
+			"export failed",
+			PollerOptions{
+				Concurrency: 2,
+				MaxAttempts: 2,
+			},
+			args{exportArn: "arn:aws:dynamodb:us-east-1:123456789012:table/my-table/export/1234-5678"},
+			func(mockClient *ddb.MockClient) {
+				describeExport(
+					mockClient,
+					&types.ExportDescription{
+						ExportStatus:   types.ExportStatusFailed,
+						FailureCode:    aws.String("S3NoSuchBucket"),
+						FailureMessage: aws.String("bucket not found")}).
+					Times(1)
+			},
+			&ExportFailedError{
+				ExportArn:      "arn:aws:dynamodb:us-east-1:123456789012:table/my-table/export/1234-5678",
+				FailureCode:    "S3NoSuchBucket",
+				FailureMessage: "bucket not found",
+			},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			poller, err := NewPoller(tc.options)
+			mockClient := ddb.NewMockClient(ctrl)
+			tc.onMock(mockClient)
+			poller, err := NewPoller(tc.options, WithClient(mockClient))
 			if err != nil {
 				t.Fatalf("NewPoller(): %s", err)
 			}
-			mockClient := ddb.NewMockClient(ctrl)
-			tc.onMock(mockClient)
-			poller.client = mockClient
 
 			ctx := context.Background()
-			err = poller.PollExport(ctx, tc.args.exportArn)
+			_, err = poller.PollExport(ctx, tc.args.exportArn)
 			assertErr(t, err, tc.want)
 		})
 	}
@@ -260,27 +284,350 @@ func TestPoller_PollExportOnTable(t *testing.T) {
 			},
 			errors.New("oops"),
 		},
+		{
+			"in-progress exports found on later pages",
+			PollerOptions{
+				Concurrency:         2,
+				MaxAttempts:         1,
+				ListExportsPageSize: 1,
+			},
+			args{tableArn: "arn:aws:dynamodb:us-east-1:123456789012:table/my-table"},
+			func(mockClient *ddb.MockClient) {
+				seq(
+					listExportsPage(
+						mockClient,
+						"",
+						[]types.ExportSummary{{
+							ExportArn:    aws.String("arn:aws:dynamodb:us-east-1:123456789012:table/my-table/export/1234-5678"),
+							ExportStatus: types.ExportStatusCompleted}},
+						"page-2").
+						Times(1),
+					listExportsPage(
+						mockClient,
+						"page-2",
+						[]types.ExportSummary{{
+							ExportArn:    aws.String("arn:aws:dynamodb:us-east-1:123456789012:table/my-table/export/9012-3456"),
+							ExportStatus: types.ExportStatusInProgress}},
+						"").
+						Times(1),
+				)
+				describeExport(
+					mockClient,
+					&types.ExportDescription{ExportStatus: types.ExportStatusInProgress}).
+					Times(1)
+			},
+			ErrExportHasNotBeenFinished,
+		},
+		{
+			"pages over the cap are not scanned",
+			PollerOptions{
+				Concurrency:         2,
+				MaxAttempts:         1,
+				ListExportsPageSize: 1,
+				MaxListExportsPages: 1,
+			},
+			args{tableArn: "arn:aws:dynamodb:us-east-1:123456789012:table/my-table"},
+			func(mockClient *ddb.MockClient) {
+				listExportsPage(
+					mockClient,
+					"",
+					[]types.ExportSummary{{
+						ExportArn:    aws.String("arn:aws:dynamodb:us-east-1:123456789012:table/my-table/export/1234-5678"),
+						ExportStatus: types.ExportStatusCompleted}},
+					"page-2").
+					Times(1)
+			},
+			nil,
+		},
+		{
+			"ListExports error on later pages",
+			PollerOptions{
+				Concurrency: 2,
+				MaxAttempts: 1,
+			},
+			args{tableArn: "arn:aws:dynamodb:us-east-1:123456789012:table/my-table"},
+			func(mockClient *ddb.MockClient) {
+				seq(
+					listExportsPage(mockClient, "", nil, "page-2").Times(1),
+					mockClient.EXPECT().
+						ListExports(gomock.Any(), listExportsInputWithToken("page-2")).
+						Return(nil, errors.New("oops")).
+						Times(1),
+				)
+			},
+			errors.New("ListExports(): oops"),
+		},
+		{
+			"export failed",
+			PollerOptions{
+				Concurrency: 2,
+				MaxAttempts: 2,
+			},
+			args{tableArn: "arn:aws:dynamodb:us-east-1:123456789012:table/my-table"},
+			func(mockClient *ddb.MockClient) {
+				listExports(
+					mockClient,
+					[]types.ExportSummary{{
+						ExportArn:    aws.String("arn:aws:dynamodb:us-east-1:123456789012:table/my-table/export/9012-3456"),
+						ExportStatus: types.ExportStatusInProgress}}).
+					Times(1)
+				describeExport(
+					mockClient,
+					&types.ExportDescription{
+						ExportStatus:   types.ExportStatusFailed,
+						FailureCode:    aws.String("S3NoSuchBucket"),
+						FailureMessage: aws.String("bucket not found")}).
+					Times(1)
+			},
+			&ExportFailedError{
+				ExportArn:      "arn:aws:dynamodb:us-east-1:123456789012:table/my-table/export/9012-3456",
+				FailureCode:    "S3NoSuchBucket",
+				FailureMessage: "bucket not found",
+			},
+		},
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			defer ctrl.Finish()
 
-			poller, err := NewPoller(tc.options)
+			mockClient := ddb.NewMockClient(ctrl)
+			tc.onMock(mockClient)
+			poller, err := NewPoller(tc.options, WithClient(mockClient))
 			if err != nil {
 				t.Fatalf("NewPoller(): %s", err)
 			}
-			mockClient := ddb.NewMockClient(ctrl)
-			tc.onMock(mockClient)
-			poller.client = mockClient
 
 			ctx := context.Background()
-			err = poller.PollExportsOnTable(ctx, tc.args.tableArn)
+			_, err = poller.PollExportsOnTable(ctx, tc.args.tableArn)
 			assertErr(t, err, tc.want)
 		})
 	}
 }
 
+func TestPoller_PollExportsOnTable_results(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := ddb.NewMockClient(ctrl)
+	exportArn := "arn:aws:dynamodb:us-east-1:123456789012:table/my-table/export/9012-3456"
+	startTime := time.Date(2022, time.August, 1, 0, 0, 0, 0, time.UTC)
+	completed := &types.ExportDescription{
+		ExportArn:       aws.String(exportArn),
+		ExportStatus:    types.ExportStatusCompleted,
+		StartTime:       aws.Time(startTime),
+		EndTime:         aws.Time(startTime.Add(time.Minute * 5)),
+		ExportTime:      aws.Time(startTime),
+		S3Bucket:        aws.String("my-bucket"),
+		S3Prefix:        aws.String("exports/my-table"),
+		ExportManifest:  aws.String("exports/my-table/AWSDynamoDB/9012-3456/manifest-summary.json"),
+		ItemCount:       aws.Int64(42),
+		BilledSizeBytes: aws.Int64(1024),
+	}
+	listExports(
+		mockClient,
+		[]types.ExportSummary{
+			{ExportArn: aws.String(exportArn), ExportStatus: types.ExportStatusInProgress},
+			{ExportArn: aws.String("arn:aws:dynamodb:us-east-1:123456789012:table/my-table/export/1234-5678"), ExportStatus: types.ExportStatusCompleted},
+		}).
+		Times(1)
+	seq(
+		describeExport(mockClient, &types.ExportDescription{ExportStatus: types.ExportStatusInProgress}).Times(1),
+		describeExport(mockClient, completed).Times(1),
+	)
+	poller, err := NewPoller(PollerOptions{Concurrency: 2, MaxAttempts: 3}, WithClient(mockClient))
+	if err != nil {
+		t.Fatalf("NewPoller(): %s", err)
+	}
+
+	got, err := poller.PollExportsOnTable(context.Background(), "arn:aws:dynamodb:us-east-1:123456789012:table/my-table")
+	if err != nil {
+		t.Fatalf("PollExportsOnTable(): %s", err)
+	}
+	want := []*ExportResult{
+		{
+			ExportArn:       exportArn,
+			Status:          types.ExportStatusCompleted,
+			StartTime:       startTime,
+			EndTime:         startTime.Add(time.Minute * 5),
+			ExportTime:      startTime,
+			S3Bucket:        "my-bucket",
+			S3Prefix:        "exports/my-table",
+			ExportManifest:  "exports/my-table/AWSDynamoDB/9012-3456/manifest-summary.json",
+			ItemCount:       42,
+			BilledSizeBytes: 1024,
+			Polls:           2,
+			Description:     completed,
+		},
+	}
+	for _, r := range got {
+		if r.WaitDuration <= 0 {
+			t.Errorf("WaitDuration must be positive but got %s", r.WaitDuration)
+		}
+		r.WaitDuration = 0
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("results:\n\twant=%#v\n\tgot=%#v", want, got)
+	}
+}
+
+func TestPoller_PollExportsOnTable_filter(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	tableArn := "arn:aws:dynamodb:us-east-1:123456789012:table/my-table"
+	startTime := time.Date(2023, time.October, 1, 0, 0, 0, 0, time.UTC)
+	inProgress := func(exportArn string, bucket string) *types.ExportDescription {
+		return &types.ExportDescription{
+			ExportArn:    aws.String(exportArn),
+			ExportStatus: types.ExportStatusInProgress,
+			ExportType:   types.ExportTypeFullExport,
+			StartTime:    aws.Time(startTime),
+			S3Bucket:     aws.String(bucket),
+		}
+	}
+	testCases := []struct {
+		name     string
+		filter   ExportFilter
+		onMock   func(mockClient *ddb.MockClient)
+		wantArns []string
+	}{
+		{
+			"type only, no DescribeExport before polling",
+			ExportFilter{ExportTypes: []types.ExportType{types.ExportTypeIncrementalExport}},
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{
+					{ExportArn: aws.String(tableArn + "/export/1"), ExportStatus: types.ExportStatusInProgress, ExportType: types.ExportTypeFullExport},
+					{ExportArn: aws.String(tableArn + "/export/2"), ExportStatus: types.ExportStatusInProgress, ExportType: types.ExportTypeIncrementalExport},
+				}).Times(1)
+				describeExportOf(mockClient, tableArn+"/export/2", &types.ExportDescription{ExportArn: aws.String(tableArn + "/export/2"), ExportStatus: types.ExportStatusCompleted}).Times(1)
+			},
+			[]string{tableArn + "/export/2"},
+		},
+		{
+			"bucket, statuses ignored",
+			ExportFilter{Statuses: []types.ExportStatus{types.ExportStatusCompleted}, S3Bucket: "my-bucket"},
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{
+					{ExportArn: aws.String(tableArn + "/export/1"), ExportStatus: types.ExportStatusInProgress},
+					{ExportArn: aws.String(tableArn + "/export/2"), ExportStatus: types.ExportStatusInProgress},
+					{ExportArn: aws.String(tableArn + "/export/3"), ExportStatus: types.ExportStatusCompleted},
+				}).Times(1)
+				seq(
+					describeExportOf(mockClient, tableArn+"/export/1", inProgress(tableArn+"/export/1", "my-bucket")).Times(1),
+					describeExportOf(mockClient, tableArn+"/export/1", &types.ExportDescription{ExportArn: aws.String(tableArn + "/export/1"), ExportStatus: types.ExportStatusCompleted}).Times(1),
+				)
+				describeExportOf(mockClient, tableArn+"/export/2", inProgress(tableArn+"/export/2", "other-bucket")).Times(1)
+			},
+			[]string{tableArn + "/export/1"},
+		},
+		{
+			"nothing matched",
+			ExportFilter{StartedAfter: startTime.Add(time.Hour)},
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{
+					{ExportArn: aws.String(tableArn + "/export/1"), ExportStatus: types.ExportStatusInProgress},
+				}).Times(1)
+				describeExportOf(mockClient, tableArn+"/export/1", inProgress(tableArn+"/export/1", "my-bucket")).Times(1)
+			},
+			[]string{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := ddb.NewMockClient(ctrl)
+			tc.onMock(mockClient)
+			poller, err := NewPoller(PollerOptions{Concurrency: 1, MaxAttempts: 1, Filter: tc.filter}, WithClient(mockClient))
+			if err != nil {
+				t.Fatalf("NewPoller(): %s", err)
+			}
+			results, err := poller.PollExportsOnTable(context.Background(), tableArn)
+			if err != nil {
+				t.Fatalf("PollExportsOnTable(): %s", err)
+			}
+			gotArns := make([]string, 0, len(results))
+			for _, r := range results {
+				gotArns = append(gotArns, r.ExportArn)
+			}
+			if !reflect.DeepEqual(gotArns, tc.wantArns) {
+				t.Errorf("results:\n\twant=%v\n\tgot=%v", tc.wantArns, gotArns)
+			}
+		})
+	}
+}
+
+func TestPoller_PollExportsOnTable_throttledWhileFiltering(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tableArn := "arn:aws:dynamodb:us-east-1:123456789012:table/my-table"
+	exportArn := tableArn + "/export/1"
+	throttled := &smithy.GenericAPIError{Code: "ThrottlingException", Message: "slow down", Fault: smithy.FaultClient}
+	mockClient := ddb.NewMockClient(ctrl)
+	seq(
+		mockClient.EXPECT().ListExports(gomock.Any(), gomock.Any()).Return(nil, throttled).Times(1),
+		listExports(mockClient, []types.ExportSummary{{ExportArn: aws.String(exportArn), ExportStatus: types.ExportStatusInProgress}}).Times(1),
+	)
+	seq(
+		mockClient.EXPECT().DescribeExport(gomock.Any(), gomock.Any()).Return(nil, throttled).Times(1),
+		describeExportOf(mockClient, exportArn, &types.ExportDescription{ExportArn: aws.String(exportArn), ExportStatus: types.ExportStatusInProgress, S3Bucket: aws.String("my-bucket")}).Times(1),
+		describeExportOf(mockClient, exportArn, &types.ExportDescription{ExportArn: aws.String(exportArn), ExportStatus: types.ExportStatusCompleted, S3Bucket: aws.String("my-bucket")}).Times(1),
+	)
+	poller, err := NewPoller(PollerOptions{Concurrency: 1, MaxAttempts: 2, InitialDelay: time.Millisecond, MaxDelay: time.Millisecond, Filter: ExportFilter{S3Bucket: "my-bucket"}}, WithClient(mockClient))
+	if err != nil {
+		t.Fatalf("NewPoller(): %s", err)
+	}
+	results, err := poller.PollExportsOnTable(context.Background(), tableArn)
+	if err != nil {
+		t.Fatalf("PollExportsOnTable(): %s", err)
+	}
+	if len(results) != 1 || results[0].Status != types.ExportStatusCompleted {
+		t.Errorf("want the export completed but got %+v", results)
+	}
+}
+
+func TestPoller_PollExportsOnTable_exportFailedError(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := ddb.NewMockClient(ctrl)
+	listExports(
+		mockClient,
+		[]types.ExportSummary{{
+			ExportArn:    aws.String("arn:aws:dynamodb:us-east-1:123456789012:table/my-table/export/9012-3456"),
+			ExportStatus: types.ExportStatusInProgress}}).
+		Times(1)
+	describeExport(
+		mockClient,
+		&types.ExportDescription{ExportStatus: types.ExportStatusFailed, FailureCode: aws.String("S3NoSuchBucket")}).
+		Times(1)
+	poller, err := NewPoller(PollerOptions{Concurrency: 2, MaxAttempts: 1}, WithClient(mockClient))
+	if err != nil {
+		t.Fatalf("NewPoller(): %s", err)
+	}
+
+	_, err = poller.PollExportsOnTable(context.Background(), "arn:aws:dynamodb:us-east-1:123456789012:table/my-table")
+	var failedErr *ExportFailedError
+	if !errors.As(err, &failedErr) {
+		t.Fatalf("expected ExportFailedError but got: %#v", err)
+	}
+	if failedErr.FailureCode != "S3NoSuchBucket" {
+		t.Errorf("FailureCode:\n\twant=%s\n\tgot=%s", "S3NoSuchBucket", failedErr.FailureCode)
+	}
+}
+
 func TestPollerOptions_validate(t *testing.T) {
 	testCase := []struct {
 		name    string
@@ -290,6 +637,13 @@ func TestPollerOptions_validate(t *testing.T) {
 		{"ok", PollerOptions{Concurrency: 1, MaxAttempts: 1}, nil},
 		{"invalid concurrency", PollerOptions{Concurrency: 0, MaxAttempts: 1}, ErrConcurrencyMustBePositive},
 		{"zero maxAttmpts", PollerOptions{Concurrency: 1, MaxAttempts: 0}, nil},
+		{"max list exports page size", PollerOptions{Concurrency: 1, ListExportsPageSize: 25}, nil},
+		{"too large list exports page size", PollerOptions{Concurrency: 1, ListExportsPageSize: 26}, ErrListExportsPageSizeOutOfRange},
+		{"negative list exports page size", PollerOptions{Concurrency: 1, ListExportsPageSize: -1}, ErrListExportsPageSizeOutOfRange},
+		{"negative max list exports pages", PollerOptions{Concurrency: 1, MaxListExportsPages: -1}, ErrMaxListExportsPagesMustNotBeNegative},
+		{"negative quorum", PollerOptions{Concurrency: 1, Quorum: -1}, ErrQuorumMustNotBeNegative},
+		{"negative request rate", PollerOptions{Concurrency: 1, RequestRate: -1}, ErrRequestRateMustNotBeNegative},
+		{"negative request burst", PollerOptions{Concurrency: 1, RequestBurst: -1}, ErrRequestBurstMustNotBeNegative},
 	}
 	for _, tc := range testCase {
 		t.Run(tc.name, func(t *testing.T) {
@@ -346,12 +700,50 @@ func listExports(mockClient *ddb.MockClient, summaries []types.ExportSummary) *g
 		Return(&dynamodb.ListExportsOutput{ExportSummaries: summaries}, nil)
 }
 
+func listExportsPage(mockClient *ddb.MockClient, token string, summaries []types.ExportSummary, nextToken string) *gomock.Call {
+	out := &dynamodb.ListExportsOutput{ExportSummaries: summaries}
+	if nextToken != "" {
+		out.NextToken = aws.String(nextToken)
+	}
+	return mockClient.EXPECT().
+		ListExports(gomock.Any(), listExportsInputWithToken(token)).
+		Return(out, nil)
+}
+
+type listExportsInputMatcher struct {
+	token string
+}
+
+var _ gomock.Matcher = listExportsInputMatcher{}
+
+func listExportsInputWithToken(token string) gomock.Matcher {
+	return listExportsInputMatcher{token: token}
+}
+
+func (m listExportsInputMatcher) Matches(x interface{}) bool {
+	input, ok := x.(*dynamodb.ListExportsInput)
+	if !ok {
+		return false
+	}
+	return aws.ToString(input.NextToken) == m.token
+}
+
+func (m listExportsInputMatcher) String() string {
+	return fmt.Sprintf("ListExportsInput with NextToken=%q", m.token)
+}
+
 func describeExport(mockClient *ddb.MockClient, description *types.ExportDescription) *gomock.Call {
 	return mockClient.EXPECT().
 		DescribeExport(gomock.Any(), gomock.Any()).
 		Return(&dynamodb.DescribeExportOutput{ExportDescription: description}, nil)
 }
 
+func describeExportOf(mockClient *ddb.MockClient, exportArn string, description *types.ExportDescription) *gomock.Call {
+	return mockClient.EXPECT().
+		DescribeExport(gomock.Any(), &dynamodb.DescribeExportInput{ExportArn: aws.String(exportArn)}).
+		Return(&dynamodb.DescribeExportOutput{ExportDescription: description}, nil)
+}
+
 func seq(calls ...*gomock.Call) *gomock.Call {
 	if len(calls) == 0 {
 		panic(errors.New("calls must be given"))
@@ -367,3 +759,5 @@ func seq(calls ...*gomock.Call) *gomock.Call {
 	}
 	return curr
 }
+
+var _ Client = (*ddb.MockClient)(nil)
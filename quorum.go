@@ -0,0 +1,67 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// QuorumAll is the quorum that means the Poller waits until all of the export jobs finish
+	QuorumAll = 0
+
+	// QuorumAny is the quorum that means the Poller returns as soon as any export job completes
+	QuorumAny = 1
+)
+
+// ErrQuorumMustNotBeNegative is an error that means given quorum is negative
+var ErrQuorumMustNotBeNegative = errors.New("quorum must not be negative")
+
+// quorum tracks how many export jobs completed and cancels polling the rest once enough of them complete.
+type quorum struct {
+	mux       sync.Mutex
+	want      int
+	completed int
+	reached   bool
+	cancel    func()
+}
+
+// newQuorum returns the quorum of want export jobs among total, and the context canceled when the quorum is reached.
+//
+// The quorum never cancels the context if want is QuorumAll or not less than total.
+func newQuorum(ctx context.Context, want, total int) (*quorum, context.Context) {
+	if want > total {
+		log.Warn().Int("quorum", want).Int("exports", total).Msg("quorum is greater than the number of exports; waiting for all of them")
+	}
+	if want == QuorumAll || want >= total {
+		return &quorum{cancel: noop}, ctx
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return &quorum{want: want, cancel: cancel}, ctx
+}
+
+// complete counts the completed export job and cancels polling the rest if the quorum is reached.
+func (q *quorum) complete() {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+	q.completed++
+	if q.want > 0 && q.completed >= q.want && !q.reached {
+		q.reached = true
+		q.cancel()
+	}
+}
+
+// abandoned returns true if polling the export job stopped with the err because the quorum was reached.
+func (q *quorum) abandoned(err error) bool {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+	return q.reached && errors.Is(err, context.Canceled)
+}
+
+func (q *quorum) isReached() bool {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+	return q.reached
+}
@@ -0,0 +1,204 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aereal/dynamodb-export-poller/internal/ddb"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/golang/mock/gomock"
+)
+
+func TestPoller_PollExportsOnTable_quorum(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	tableArn := "arn:aws:dynamodb:us-east-1:123456789012:table/my-table"
+	exportArns := []string{tableArn + "/export/1", tableArn + "/export/2", tableArn + "/export/3"}
+	summaries := make([]types.ExportSummary, len(exportArns))
+	for i, exportArn := range exportArns {
+		summaries[i] = types.ExportSummary{ExportArn: aws.String(exportArn), ExportStatus: types.ExportStatusInProgress}
+	}
+	described := func(exportArn string, status types.ExportStatus) *types.ExportDescription {
+		return &types.ExportDescription{ExportArn: aws.String(exportArn), ExportStatus: status}
+	}
+	type wantResult struct {
+		status    types.ExportStatus
+		abandoned bool
+	}
+	testCases := []struct {
+		name        string
+		quorum      int
+		concurrency int64
+		onMock      func(mockClient *ddb.MockClient)
+		want        []wantResult
+		wantErr     error
+	}{
+		{
+			"any; the rest are not polled",
+			QuorumAny,
+			1,
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, summaries).Times(1)
+				describeExportOf(mockClient, exportArns[0], described(exportArns[0], types.ExportStatusCompleted)).Times(1)
+			},
+			[]wantResult{{status: types.ExportStatusCompleted}, {abandoned: true}, {abandoned: true}},
+			nil,
+		},
+		{
+			"any; the running one is abandoned",
+			QuorumAny,
+			2,
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, summaries).Times(1)
+				describeExportOf(mockClient, exportArns[0], described(exportArns[0], types.ExportStatusCompleted)).Times(1)
+				describeExportOf(mockClient, exportArns[1], described(exportArns[1], types.ExportStatusInProgress)).AnyTimes()
+			},
+			nil,
+			nil,
+		},
+		{
+			"first 2; failed export before the quorum is reported",
+			2,
+			1,
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, summaries).Times(1)
+				describeExportOf(mockClient, exportArns[0], &types.ExportDescription{ExportArn: aws.String(exportArns[0]), ExportStatus: types.ExportStatusFailed, FailureCode: aws.String("code"), FailureMessage: aws.String("msg")}).Times(1)
+				describeExportOf(mockClient, exportArns[1], described(exportArns[1], types.ExportStatusCompleted)).Times(1)
+				describeExportOf(mockClient, exportArns[2], described(exportArns[2], types.ExportStatusCompleted)).Times(1)
+			},
+			[]wantResult{{status: types.ExportStatusFailed}, {status: types.ExportStatusCompleted}, {status: types.ExportStatusCompleted}},
+			&ExportFailedError{ExportArn: exportArns[0], FailureCode: "code", FailureMessage: "msg"},
+		},
+		{
+			"first 2; not reached",
+			2,
+			1,
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, summaries).Times(1)
+				describeExportOf(mockClient, exportArns[0], &types.ExportDescription{ExportArn: aws.String(exportArns[0]), ExportStatus: types.ExportStatusFailed, FailureCode: aws.String("code"), FailureMessage: aws.String("msg")}).Times(1)
+				describeExportOf(mockClient, exportArns[1], described(exportArns[1], types.ExportStatusCompleted)).Times(1)
+				describeExportOf(mockClient, exportArns[2], &types.ExportDescription{ExportArn: aws.String(exportArns[2]), ExportStatus: types.ExportStatusFailed, FailureCode: aws.String("code"), FailureMessage: aws.String("msg")}).Times(1)
+			},
+			[]wantResult{{status: types.ExportStatusFailed}, {status: types.ExportStatusCompleted}, {status: types.ExportStatusFailed}},
+			&ExportFailedError{ExportArn: exportArns[0], FailureCode: "code", FailureMessage: "msg"},
+		},
+		{
+			"more than exports means all",
+			5,
+			1,
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, summaries).Times(1)
+				for _, exportArn := range exportArns {
+					describeExportOf(mockClient, exportArn, described(exportArn, types.ExportStatusCompleted)).Times(1)
+				}
+			},
+			[]wantResult{{status: types.ExportStatusCompleted}, {status: types.ExportStatusCompleted}, {status: types.ExportStatusCompleted}},
+			nil,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := ddb.NewMockClient(ctrl)
+			tc.onMock(mockClient)
+			poller, err := NewPoller(PollerOptions{Concurrency: tc.concurrency, InitialDelay: time.Hour, MaxDelay: time.Hour, Quorum: tc.quorum}, WithClient(mockClient))
+			if err != nil {
+				t.Fatalf("NewPoller(): %s", err)
+			}
+			results, err := poller.PollExportsOnTable(context.Background(), tableArn)
+			var failedErr *ExportFailedError
+			if tc.wantErr != nil && !errors.As(err, &failedErr) {
+				t.Errorf("error: want ExportFailedError but got %v", err)
+			}
+			if tc.wantErr == nil && err != nil {
+				t.Errorf("PollExportsOnTable(): %s", err)
+			}
+			if len(results) != len(exportArns) {
+				t.Fatalf("results: want %d but got %d", len(exportArns), len(results))
+			}
+			if tc.want == nil {
+				// the status of the running export depends on whether it was described before the quorum is reached
+				if !results[1].Abandoned || !results[2].Abandoned {
+					t.Errorf("want the rest abandoned but got %v, %v", results[1].Abandoned, results[2].Abandoned)
+				}
+				return
+			}
+			got := make([]wantResult, len(results))
+			for i, r := range results {
+				got[i] = wantResult{status: r.Status, abandoned: r.Abandoned}
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("results:\n\twant=%+v\n\tgot=%+v", tc.want, got)
+			}
+		})
+	}
+}
+
+type giveUpObserver struct {
+	NopObserver
+	mu        sync.Mutex
+	abandoned map[string]bool
+}
+
+func (o *giveUpObserver) OnGiveUp(ctx context.Context, result *ExportResult, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.abandoned[result.ExportArn] = result.Abandoned
+}
+
+func TestPoller_PollExportsOnTable_quorumObserver(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tableArn := "arn:aws:dynamodb:us-east-1:123456789012:table/my-table"
+	exportArns := []string{tableArn + "/export/1", tableArn + "/export/2"}
+	mockClient := ddb.NewMockClient(ctrl)
+	listExports(mockClient, []types.ExportSummary{
+		{ExportArn: aws.String(exportArns[0]), ExportStatus: types.ExportStatusInProgress},
+		{ExportArn: aws.String(exportArns[1]), ExportStatus: types.ExportStatusInProgress},
+	}).Times(1)
+	running := make(chan struct{})
+	mockClient.EXPECT().
+		DescribeExport(gomock.Any(), &dynamodb.DescribeExportInput{ExportArn: aws.String(exportArns[1])}).
+		DoAndReturn(func(ctx context.Context, input *dynamodb.DescribeExportInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeExportOutput, error) {
+			close(running)
+			return &dynamodb.DescribeExportOutput{ExportDescription: &types.ExportDescription{ExportArn: input.ExportArn, ExportStatus: types.ExportStatusInProgress}}, nil
+		}).
+		Times(1)
+	mockClient.EXPECT().
+		DescribeExport(gomock.Any(), &dynamodb.DescribeExportInput{ExportArn: aws.String(exportArns[0])}).
+		DoAndReturn(func(ctx context.Context, input *dynamodb.DescribeExportInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeExportOutput, error) {
+			// complete after the other export started polling so that it is abandoned while running
+			<-running
+			return &dynamodb.DescribeExportOutput{ExportDescription: &types.ExportDescription{ExportArn: input.ExportArn, ExportStatus: types.ExportStatusCompleted}}, nil
+		}).
+		Times(1)
+	observer := &giveUpObserver{abandoned: map[string]bool{}}
+	poller, err := NewPoller(PollerOptions{Concurrency: 2, InitialDelay: time.Hour, MaxDelay: time.Hour, Quorum: QuorumAny, Observer: observer}, WithClient(mockClient))
+	if err != nil {
+		t.Fatalf("NewPoller(): %s", err)
+	}
+	results, err := poller.PollExportsOnTable(context.Background(), tableArn)
+	if err != nil {
+		t.Fatalf("PollExportsOnTable(): %s", err)
+	}
+	if !results[1].Abandoned {
+		t.Errorf("want %s abandoned", exportArns[1])
+	}
+	want := map[string]bool{exportArns[1]: true}
+	if !reflect.DeepEqual(observer.abandoned, want) {
+		t.Errorf("OnGiveUp:\n\twant=%v\n\tgot=%v", want, observer.abandoned)
+	}
+}
@@ -0,0 +1,108 @@
+package ddbexportpoller
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ExportResult is a result of an export job the Poller tracked.
+type ExportResult struct {
+	// ExportArn is the ARN of the export job
+	ExportArn string
+
+	// Status is the last observed status of the export job.
+	//
+	// It is empty if the Poller could not describe the export job at all.
+	Status types.ExportStatus
+
+	// StartTime is the time at which the export job began
+	StartTime time.Time
+
+	// EndTime is the time at which the export job completed
+	EndTime time.Time
+
+	// ExportTime is the point in time from which table data was exported
+	ExportTime time.Time
+
+	// S3Bucket is the name of the bucket the data was exported to
+	S3Bucket string
+
+	// S3Prefix is the key prefix of the exported data
+	S3Prefix string
+
+	// ExportManifest is the name of the manifest file for the export job
+	ExportManifest string
+
+	// ItemCount is the number of items exported
+	ItemCount int64
+
+	// BilledSizeBytes is the billable size of the table export
+	BilledSizeBytes int64
+
+	// Polls is the number of export job status check requests the Poller sent
+	Polls int
+
+	// WaitDuration is the wall-clock time the Poller waited the export job
+	WaitDuration time.Duration
+
+	// Description is the last observed raw description of the export job
+	Description *types.ExportDescription
+
+	// Abandoned means the Poller stopped polling the export job because PollerOptions.Quorum was reached while it was still running
+	Abandoned bool
+}
+
+type exportTracker struct {
+	exportArn   string
+	startedAt   time.Time
+	polls       int
+	throttles   int
+	description *types.ExportDescription
+	onObserve   func(prev, curr *types.ExportDescription)
+	onSettle    func(result *ExportResult, err error)
+	abandoned   func(err error) bool
+}
+
+func newExportTracker(exportArn string) *exportTracker {
+	return &exportTracker{exportArn: exportArn, startedAt: time.Now()}
+}
+
+func (t *exportTracker) observe(description *types.ExportDescription) {
+	prev := t.description
+	t.description = description
+	if t.onObserve != nil {
+		t.onObserve(prev, description)
+	}
+}
+
+func (t *exportTracker) settle(err error) *ExportResult {
+	result := t.result()
+	result.Abandoned = t.abandoned != nil && t.abandoned(err)
+	if t.onSettle != nil {
+		t.onSettle(result, err)
+	}
+	return result
+}
+
+func (t *exportTracker) result() *ExportResult {
+	ret := &ExportResult{
+		ExportArn:    t.exportArn,
+		Polls:        t.polls,
+		WaitDuration: time.Since(t.startedAt),
+		Description:  t.description,
+	}
+	if d := t.description; d != nil {
+		ret.Status = d.ExportStatus
+		ret.StartTime = aws.ToTime(d.StartTime)
+		ret.EndTime = aws.ToTime(d.EndTime)
+		ret.ExportTime = aws.ToTime(d.ExportTime)
+		ret.S3Bucket = aws.ToString(d.S3Bucket)
+		ret.S3Prefix = aws.ToString(d.S3Prefix)
+		ret.ExportManifest = aws.ToString(d.ExportManifest)
+		ret.ItemCount = aws.ToInt64(d.ItemCount)
+		ret.BilledSizeBytes = aws.ToInt64(d.BilledSizeBytes)
+	}
+	return ret
+}
@@ -0,0 +1,44 @@
+package ddbexportpoller
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+func TestExportTracker_result(t *testing.T) {
+	testCases := []struct {
+		name        string
+		description *types.ExportDescription
+		polls       int
+		wantStatus  types.ExportStatus
+		wantItems   int64
+	}{
+		{"never described", nil, 1, "", 0},
+		{"in progress", &types.ExportDescription{ExportStatus: types.ExportStatusInProgress}, 2, types.ExportStatusInProgress, 0},
+		{"completed", &types.ExportDescription{ExportStatus: types.ExportStatusCompleted, ItemCount: aws.Int64(10)}, 3, types.ExportStatusCompleted, 10},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tracker := newExportTracker("arn:aws:dynamodb:us-east-1:123456789012:table/my-table/export/9012-3456")
+			tracker.polls = tc.polls
+			if tc.description != nil {
+				tracker.observe(tc.description)
+			}
+			got := tracker.result()
+			if got.ExportArn != tracker.exportArn {
+				t.Errorf("ExportArn:\n\twant=%s\n\tgot=%s", tracker.exportArn, got.ExportArn)
+			}
+			if got.Status != tc.wantStatus {
+				t.Errorf("Status:\n\twant=%s\n\tgot=%s", tc.wantStatus, got.Status)
+			}
+			if got.ItemCount != tc.wantItems {
+				t.Errorf("ItemCount:\n\twant=%d\n\tgot=%d", tc.wantItems, got.ItemCount)
+			}
+			if got.Polls != tc.polls {
+				t.Errorf("Polls:\n\twant=%d\n\tgot=%d", tc.polls, got.Polls)
+			}
+		})
+	}
+}
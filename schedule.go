@@ -0,0 +1,251 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// maxExportHistory is the number of completed export jobs on the table used to predict the duration of the export job
+	maxExportHistory = 5
+
+	// defaultExportOverhead is a rough estimate of the time a full export takes regardless of the table size
+	defaultExportOverhead = 5 * time.Minute
+
+	// defaultExportThroughput is a rough estimate of the bytes a full export processes per second
+	defaultExportThroughput = 100 << 20
+
+	// defaultAdaptiveMaxDelay is the longest interval of the adaptive schedule if PollerOptions.AdaptiveMaxDelay is zero
+	defaultAdaptiveMaxDelay = 10 * time.Minute
+
+	// predictionTTL is how long the predicted duration is reused for the export jobs of the same type on the table
+	predictionTTL = 10 * time.Minute
+)
+
+type predictionKey struct {
+	tableArn   string
+	exportType types.ExportType
+}
+
+type prediction struct {
+	mux       sync.Mutex
+	duration  time.Duration
+	ok        bool
+	expiresAt time.Time
+}
+
+// predictions caches the predicted durations of export jobs by the table and the export type for predictionTTL.
+type predictions struct {
+	mux sync.Mutex
+	m   map[predictionKey]*prediction
+}
+
+func (c *predictions) get(key predictionKey) *prediction {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if c.m == nil {
+		c.m = map[predictionKey]*prediction{}
+	}
+	if _, ok := c.m[key]; !ok {
+		c.m[key] = &prediction{}
+	}
+	return c.m[key]
+}
+
+// predictExportDuration predicts how long the export job of the type on the table takes from start to end.
+//
+// A full export is predicted from the throughput of the past completed full exports and the current table size, and an incremental export is predicted from the average duration of the past ones.
+// It returns false if neither the history nor the table size is available.
+// The prediction is not cached if the context is done while predicting, so that the next caller predicts again.
+func (p *Poller) predictExportDuration(ctx context.Context, tableArn string, exportType types.ExportType) (time.Duration, bool) {
+	pred := p.predictions.get(predictionKey{tableArn: tableArn, exportType: exportType})
+	pred.mux.Lock()
+	defer pred.mux.Unlock()
+	if time.Now().Before(pred.expiresAt) {
+		return pred.duration, pred.ok
+	}
+	duration, ok := p.doPredictExportDuration(ctx, tableArn, exportType)
+	if ctx.Err() != nil {
+		return duration, ok
+	}
+	pred.duration, pred.ok, pred.expiresAt = duration, ok, time.Now().Add(predictionTTL)
+	log.Debug().Str("tableArn", tableArn).Str("exportType", string(exportType)).Dur("duration", duration).Bool("predicted", ok).Msg("export duration predicted")
+	return duration, ok
+}
+
+func (p *Poller) doPredictExportDuration(ctx context.Context, tableArn string, exportType types.ExportType) (time.Duration, bool) {
+	var (
+		total  time.Duration
+		billed int64
+		n      int
+	)
+	for _, d := range p.exportHistory(ctx, tableArn, exportType) {
+		if d.StartTime == nil || d.EndTime == nil {
+			continue
+		}
+		total += d.EndTime.Sub(*d.StartTime)
+		billed += aws.ToInt64(d.BilledSizeBytes)
+		n++
+	}
+	if exportType == types.ExportTypeIncrementalExport {
+		if n == 0 {
+			return 0, false
+		}
+		return total / time.Duration(n), true
+	}
+	size, sizeOK := p.tableSize(ctx, tableArn)
+	switch {
+	case sizeOK && n > 0 && billed > 0:
+		return time.Duration(float64(total) * float64(size) / float64(billed)), true
+	case n > 0:
+		return total / time.Duration(n), true
+	case sizeOK:
+		return defaultExportOverhead + time.Duration(size/defaultExportThroughput)*time.Second, true
+	default:
+		return 0, false
+	}
+}
+
+// exportHistory returns the descriptions of the latest completed export jobs of the type on the table.
+//
+// ListExports does not promise the order of the export jobs, so all completed ones are described and the latest ones are chosen by their end time.
+//
+// Errors are logged and ignored because the history is only used for the prediction.
+func (p *Poller) exportHistory(ctx context.Context, tableArn string, exportType types.ExportType) []*types.ExportDescription {
+	summaries, err := p.listExports(ctx, tableArn)
+	if err != nil {
+		log.Debug().Err(err).Str("tableArn", tableArn).Msg("failed to list export history")
+		return nil
+	}
+	exportArns := make([]string, 0, len(summaries))
+	for _, summary := range summaries {
+		if summary.ExportStatus != types.ExportStatusCompleted {
+			continue
+		}
+		if summary.ExportType != "" && exportType != "" && summary.ExportType != exportType {
+			continue
+		}
+		exportArns = append(exportArns, aws.ToString(summary.ExportArn))
+	}
+	descriptions, err := p.describeExports(ctx, exportArns)
+	if err != nil {
+		log.Debug().Err(err).Str("tableArn", tableArn).Msg("failed to describe export history")
+		return nil
+	}
+	sort.SliceStable(descriptions, func(i, j int) bool { return exportFinishedAt(descriptions[i]).After(exportFinishedAt(descriptions[j])) })
+	if len(descriptions) > maxExportHistory {
+		descriptions = descriptions[:maxExportHistory]
+	}
+	return descriptions
+}
+
+// exportFinishedAt returns the end time of the export job, or its start time if the end time is unknown.
+func exportFinishedAt(d *types.ExportDescription) time.Time {
+	if d.EndTime != nil {
+		return *d.EndTime
+	}
+	return aws.ToTime(d.StartTime)
+}
+
+func (p *Poller) tableSize(ctx context.Context, tableArn string) (int64, bool) {
+	out, err := p.clientFor(tableArn).DescribeTable(ctx, &dynamodb.DescribeTableInput{TableName: aws.String(tableArn)})
+	if err != nil {
+		p.options.observer().OnAPIError(ctx, "DescribeTable", err)
+		log.Debug().Err(err).Str("tableArn", tableArn).Msg("failed to describe table size")
+		return 0, false
+	}
+	if out.Table == nil || out.Table.TableSizeBytes == nil {
+		return 0, false
+	}
+	return *out.Table.TableSizeBytes, true
+}
+
+// adaptiveBackoff is the BackoffStrategy that waits for the export job in proportion to the time left until its predicted completion.
+//
+// It waits half of the time left between minDelay and maxDelay, and falls back to the base strategy from its first retry once the predicted completion time has passed or if the completion is unpredictable.
+type adaptiveBackoff struct {
+	ctx      context.Context
+	poller   *Poller
+	tracker  *exportTracker
+	base     BackoffStrategy
+	minDelay time.Duration
+	maxDelay time.Duration
+
+	predicted   bool
+	expectedEnd time.Time
+	overdueFrom int
+}
+
+var _ BackoffStrategy = &adaptiveBackoff{}
+
+func (p *Poller) newAdaptiveBackoff(ctx context.Context, tracker *exportTracker) *adaptiveBackoff {
+	maxDelay := p.options.AdaptiveMaxDelay
+	if maxDelay == 0 {
+		maxDelay = defaultAdaptiveMaxDelay
+	}
+	return &adaptiveBackoff{
+		ctx:      ctx,
+		poller:   p,
+		tracker:  tracker,
+		base:     p.options.backoff(),
+		minDelay: p.options.InitialDelay,
+		maxDelay: capDelay(p.options.InitialDelay, maxDelay),
+	}
+}
+
+func (b *adaptiveBackoff) Delay(retry int, prev time.Duration) time.Duration {
+	if b.overdueFrom == 0 {
+		if end, ok := b.predictEnd(); ok {
+			if left := time.Until(end); left > 0 {
+				delay := left / 2
+				if delay < b.minDelay {
+					delay = b.minDelay
+				}
+				if delay > b.maxDelay {
+					delay = b.maxDelay
+				}
+				return delay
+			}
+		}
+		if !b.predicted {
+			// the export job has not been described yet
+			return b.base.Delay(retry, prev)
+		}
+		b.overdueFrom = retry
+		prev = 0
+	}
+	return b.base.Delay(retry-b.overdueFrom+1, prev)
+}
+
+// predictEnd predicts when the export job completes once the export job has been described.
+func (b *adaptiveBackoff) predictEnd() (time.Time, bool) {
+	if b.predicted {
+		return b.expectedEnd, !b.expectedEnd.IsZero()
+	}
+	d := b.tracker.description
+	if d == nil {
+		return time.Time{}, false
+	}
+	b.predicted = true
+	exportARN, err := ParseExportARN(b.tracker.exportArn)
+	if err != nil {
+		return time.Time{}, false
+	}
+	duration, ok := b.poller.predictExportDuration(b.ctx, exportARN.Table.String(), d.ExportType)
+	if !ok {
+		return time.Time{}, false
+	}
+	startTime := b.tracker.startedAt
+	if d.StartTime != nil {
+		startTime = *d.StartTime
+	}
+	b.expectedEnd = startTime.Add(duration)
+	return b.expectedEnd, true
+}
@@ -0,0 +1,277 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aereal/dynamodb-export-poller/internal/ddb"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/aws/smithy-go"
+	"github.com/golang/mock/gomock"
+)
+
+func TestPoller_predictExportDuration(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	tableArn := "arn:aws:dynamodb:us-east-1:123456789012:table/my-table"
+	startTime := time.Date(2023, time.October, 1, 0, 0, 0, 0, time.UTC)
+	completed := func(id string, exportType types.ExportType) types.ExportSummary {
+		return types.ExportSummary{ExportArn: aws.String(tableArn + "/export/" + id), ExportStatus: types.ExportStatusCompleted, ExportType: exportType}
+	}
+	finished := func(id string, duration time.Duration, billed int64) *types.ExportDescription {
+		return &types.ExportDescription{
+			ExportArn:       aws.String(tableArn + "/export/" + id),
+			ExportStatus:    types.ExportStatusCompleted,
+			StartTime:       aws.Time(startTime),
+			EndTime:         aws.Time(startTime.Add(duration)),
+			BilledSizeBytes: aws.Int64(billed),
+		}
+	}
+	describeTableSize := func(mockClient *ddb.MockClient, size int64) *gomock.Call {
+		return mockClient.EXPECT().
+			DescribeTable(gomock.Any(), &dynamodb.DescribeTableInput{TableName: aws.String(tableArn)}).
+			Return(&dynamodb.DescribeTableOutput{Table: &types.TableDescription{TableArn: aws.String(tableArn), TableSizeBytes: aws.Int64(size)}}, nil)
+	}
+	testCases := []struct {
+		name       string
+		exportType types.ExportType
+		onMock     func(mockClient *ddb.MockClient)
+		want       time.Duration
+		wantOK     bool
+	}{
+		{
+			"full export; throughput of the history and the table size",
+			types.ExportTypeFullExport,
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{
+					completed("1", types.ExportTypeFullExport),
+					completed("2", types.ExportTypeIncrementalExport),
+					completed("3", types.ExportTypeFullExport),
+					{ExportArn: aws.String(tableArn + "/export/4"), ExportStatus: types.ExportStatusFailed, ExportType: types.ExportTypeFullExport},
+				}).Times(1)
+				describeExportOf(mockClient, tableArn+"/export/1", finished("1", 10*time.Minute, 1<<30)).Times(1)
+				describeExportOf(mockClient, tableArn+"/export/3", finished("3", 20*time.Minute, 1<<30)).Times(1)
+				describeTableSize(mockClient, 4<<30).Times(1)
+			},
+			time.Hour,
+			true,
+		},
+		{
+			"full export; average of the history if the table size is unknown",
+			types.ExportTypeFullExport,
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{completed("1", types.ExportTypeFullExport), completed("2", types.ExportTypeFullExport)}).Times(1)
+				describeExportOf(mockClient, tableArn+"/export/1", finished("1", 10*time.Minute, 1<<30)).Times(1)
+				describeExportOf(mockClient, tableArn+"/export/2", finished("2", 20*time.Minute, 1<<30)).Times(1)
+				mockClient.EXPECT().DescribeTable(gomock.Any(), gomock.Any()).Return(nil, errors.New("oops")).Times(1)
+			},
+			15 * time.Minute,
+			true,
+		},
+		{
+			"full export; estimated from the table size without history",
+			types.ExportTypeFullExport,
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{}).Times(1)
+				describeTableSize(mockClient, 1000<<20).Times(1)
+			},
+			defaultExportOverhead + 10*time.Second,
+			true,
+		},
+		{
+			"full export; unpredictable",
+			types.ExportTypeFullExport,
+			func(mockClient *ddb.MockClient) {
+				mockClient.EXPECT().ListExports(gomock.Any(), gomock.Any()).Return(nil, &smithy.GenericAPIError{Code: "oops", Message: "oops", Fault: smithy.FaultClient}).Times(1)
+				mockClient.EXPECT().DescribeTable(gomock.Any(), gomock.Any()).Return(nil, errors.New("oops")).Times(1)
+			},
+			0,
+			false,
+		},
+		{
+			"incremental export; average of the history regardless of the table size",
+			types.ExportTypeIncrementalExport,
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{completed("1", types.ExportTypeFullExport), completed("2", types.ExportTypeIncrementalExport)}).Times(1)
+				describeExportOf(mockClient, tableArn+"/export/2", finished("2", 3*time.Minute, 1<<20)).Times(1)
+			},
+			3 * time.Minute,
+			true,
+		},
+		{
+			"incremental export; only the latest exports regardless of the listed order",
+			types.ExportTypeIncrementalExport,
+			func(mockClient *ddb.MockClient) {
+				summaries := make([]types.ExportSummary, 0, maxExportHistory+1)
+				for i := 1; i <= maxExportHistory+1; i++ {
+					id := strconv.Itoa(i)
+					summaries = append(summaries, completed(id, types.ExportTypeIncrementalExport))
+					// the last listed export is the oldest one and took much longer than the others
+					d := finished(id, 3*time.Minute, 1<<20)
+					if i == maxExportHistory+1 {
+						d = finished(id, 3*time.Hour, 1<<20)
+						d.StartTime, d.EndTime = aws.Time(startTime.Add(-24*time.Hour)), aws.Time(startTime.Add(-21*time.Hour))
+					}
+					describeExportOf(mockClient, tableArn+"/export/"+id, d).Times(1)
+				}
+				listExports(mockClient, summaries).Times(1)
+			},
+			3 * time.Minute,
+			true,
+		},
+		{
+			"incremental export; unpredictable without history",
+			types.ExportTypeIncrementalExport,
+			func(mockClient *ddb.MockClient) {
+				listExports(mockClient, []types.ExportSummary{completed("1", types.ExportTypeFullExport)}).Times(1)
+			},
+			0,
+			false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := ddb.NewMockClient(ctrl)
+			tc.onMock(mockClient)
+			poller, err := NewPoller(PollerOptions{Concurrency: 1}, WithClient(mockClient))
+			if err != nil {
+				t.Fatalf("NewPoller(): %s", err)
+			}
+			for i := 0; i < 2; i++ {
+				// the prediction is cached so the requests are sent only once
+				got, ok := poller.predictExportDuration(context.Background(), tableArn, tc.exportType)
+				if got != tc.want || ok != tc.wantOK {
+					t.Errorf("want=(%s, %v) got=(%s, %v)", tc.want, tc.wantOK, got, ok)
+				}
+			}
+		})
+	}
+}
+
+func TestPoller_predictExportDuration_cache(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tableArn := "arn:aws:dynamodb:us-east-1:123456789012:table/my-table"
+	exportArn := tableArn + "/export/1"
+	startTime := time.Date(2023, time.October, 1, 0, 0, 0, 0, time.UTC)
+	ctx, cancel := context.WithCancel(context.Background())
+	mockClient := ddb.NewMockClient(ctrl)
+	seq(
+		mockClient.EXPECT().
+			ListExports(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, input *dynamodb.ListExportsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ListExportsOutput, error) {
+				cancel()
+				return nil, context.Canceled
+			}).
+			Times(1),
+		listExports(mockClient, []types.ExportSummary{{ExportArn: aws.String(exportArn), ExportStatus: types.ExportStatusCompleted, ExportType: types.ExportTypeIncrementalExport}}).Times(2),
+	)
+	describeExportOf(mockClient, exportArn, &types.ExportDescription{ExportArn: aws.String(exportArn), ExportStatus: types.ExportStatusCompleted, StartTime: aws.Time(startTime), EndTime: aws.Time(startTime.Add(3 * time.Minute))}).Times(2)
+	poller, err := NewPoller(PollerOptions{Concurrency: 1}, WithClient(mockClient))
+	if err != nil {
+		t.Fatalf("NewPoller(): %s", err)
+	}
+	if _, ok := poller.predictExportDuration(ctx, tableArn, types.ExportTypeIncrementalExport); ok {
+		t.Error("want unpredictable while the context is canceled")
+	}
+	for i := 0; i < 2; i++ {
+		// the failure caused by the canceled context is not cached, and the prediction is reused afterwards
+		if got, ok := poller.predictExportDuration(context.Background(), tableArn, types.ExportTypeIncrementalExport); got != 3*time.Minute || !ok {
+			t.Errorf("want=(3m0s, true) got=(%s, %v)", got, ok)
+		}
+	}
+	// the expired prediction is predicted again
+	poller.predictions.get(predictionKey{tableArn: tableArn, exportType: types.ExportTypeIncrementalExport}).expiresAt = time.Now()
+	if got, ok := poller.predictExportDuration(context.Background(), tableArn, types.ExportTypeIncrementalExport); got != 3*time.Minute || !ok {
+		t.Errorf("want=(3m0s, true) got=(%s, %v)", got, ok)
+	}
+}
+
+func TestAdaptiveBackoff_Delay(t *testing.T) {
+	base := ExponentialBackoff{InitialDelay: time.Second, MaxDelay: time.Minute}
+	testCases := []struct {
+		name        string
+		described   bool
+		expectedEnd time.Duration
+		retries     []int
+		want        []time.Duration
+		tolerance   time.Duration
+	}{
+		{"far from the completion", true, 2 * time.Hour, []int{1, 2}, []time.Duration{10 * time.Minute, 10 * time.Minute}, 0},
+		{"half of the time left", true, 4 * time.Minute, []int{1}, []time.Duration{2 * time.Minute}, time.Second},
+		{"at least the min delay", true, time.Second, []int{1}, []time.Duration{5 * time.Second}, 0},
+		{"overdue", true, -time.Minute, []int{3, 4, 5}, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}, 0},
+		{"unpredictable", true, 0, []int{3, 4}, []time.Duration{time.Second, 2 * time.Second}, 0},
+		{"not described yet", false, 0, []int{2, 3}, []time.Duration{2 * time.Second, 4 * time.Second}, 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tracker := newExportTracker("arn:aws:dynamodb:us-east-1:123456789012:table/my-table/export/1")
+			b := &adaptiveBackoff{tracker: tracker, base: base, minDelay: 5 * time.Second, maxDelay: 10 * time.Minute, predicted: tc.described}
+			if tc.expectedEnd != 0 {
+				b.expectedEnd = time.Now().Add(tc.expectedEnd)
+			}
+			var prev time.Duration
+			for i, retry := range tc.retries {
+				got := b.Delay(retry, prev)
+				if diff := got - tc.want[i]; diff > tc.tolerance || -diff > tc.tolerance {
+					t.Errorf("retry %d: want=%s got=%s", retry, tc.want[i], got)
+				}
+				prev = got
+			}
+		})
+	}
+}
+
+func TestPoller_PollExportsOnTable_adaptiveScheduling(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	tableArn := "arn:aws:dynamodb:us-east-1:123456789012:table/my-table"
+	// the exports started long ago, so the predicted completion has passed and the base strategy is used
+	startTime := time.Now().Add(-24 * time.Hour)
+	inProgress := func(id string) *types.ExportDescription {
+		return &types.ExportDescription{ExportArn: aws.String(tableArn + "/export/" + id), ExportStatus: types.ExportStatusInProgress, ExportType: types.ExportTypeFullExport, StartTime: aws.Time(startTime)}
+	}
+	mockClient := ddb.NewMockClient(ctrl)
+	seq(
+		listExports(mockClient, []types.ExportSummary{
+			{ExportArn: aws.String(tableArn + "/export/1"), ExportStatus: types.ExportStatusInProgress},
+			{ExportArn: aws.String(tableArn + "/export/2"), ExportStatus: types.ExportStatusInProgress},
+		}).Times(1),
+		listExports(mockClient, []types.ExportSummary{}).Times(1),
+	)
+	mockClient.EXPECT().
+		DescribeTable(gomock.Any(), gomock.Any()).
+		Return(&dynamodb.DescribeTableOutput{Table: &types.TableDescription{TableSizeBytes: aws.Int64(1 << 20)}}, nil).
+		Times(1)
+	for _, id := range []string{"1", "2"} {
+		seq(
+			describeExportOf(mockClient, tableArn+"/export/"+id, inProgress(id)).Times(1),
+			describeExportOf(mockClient, tableArn+"/export/"+id, &types.ExportDescription{ExportArn: aws.String(tableArn + "/export/" + id), ExportStatus: types.ExportStatusCompleted}).Times(1),
+		)
+	}
+	poller, err := NewPoller(PollerOptions{Concurrency: 1, AdaptiveScheduling: true}, WithClient(mockClient))
+	if err != nil {
+		t.Fatalf("NewPoller(): %s", err)
+	}
+	if _, err := poller.PollExportsOnTable(context.Background(), tableArn); err != nil {
+		t.Fatalf("PollExportsOnTable(): %s", err)
+	}
+}
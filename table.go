@@ -0,0 +1,35 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+)
+
+// ResolveTableARN returns the ARN of the table.
+//
+// The table is either of a table ARN or a table name. The table name is resolved by DescribeTable in the region the client is configured with.
+func (p *Poller) ResolveTableARN(ctx context.Context, table string) (string, error) {
+	if table == "" || strings.HasPrefix(table, "arn:") {
+		if _, err := ParseTableARN(table); err != nil {
+			return "", err
+		}
+		return table, nil
+	}
+	reqCtx, cancel := p.options.withRequestTimeout(ctx)
+	defer cancel()
+	out, err := p.client.DescribeTable(reqCtx, &dynamodb.DescribeTableInput{TableName: aws.String(table)})
+	err = p.options.requestTimeoutError(ctx, reqCtx, "DescribeTable", err)
+	if err != nil {
+		p.options.observer().OnAPIError(ctx, "DescribeTable", err)
+		return "", fmt.Errorf("DescribeTable(%s): %w", table, err)
+	}
+	tableArn := aws.ToString(out.Table.TableArn)
+	if _, err := ParseTableARN(tableArn); err != nil {
+		return "", err
+	}
+	return tableArn, nil
+}
@@ -0,0 +1,91 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aereal/dynamodb-export-poller/internal/ddb"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/golang/mock/gomock"
+)
+
+func TestPoller_ResolveTableARN(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	tableArn := "arn:aws:dynamodb:us-east-1:123456789012:table/my-table"
+	testCases := []struct {
+		name    string
+		table   string
+		onMock  func(mockClient *ddb.MockClient)
+		want    string
+		wantErr error
+	}{
+		{
+			"table ARN",
+			tableArn,
+			func(mockClient *ddb.MockClient) {},
+			tableArn,
+			nil,
+		},
+		{
+			"invalid table ARN",
+			"arn:aws:s3:::my-bucket",
+			func(mockClient *ddb.MockClient) {},
+			"",
+			&InvalidARNError{ARN: "arn:aws:s3:::my-bucket", Resource: "table", Reason: `service must be dynamodb but got "s3"`},
+		},
+		{
+			"empty",
+			"",
+			func(mockClient *ddb.MockClient) {},
+			"",
+			ErrTableArnRequired,
+		},
+		{
+			"table name",
+			"my-table",
+			func(mockClient *ddb.MockClient) {
+				mockClient.EXPECT().
+					DescribeTable(gomock.Any(), &dynamodb.DescribeTableInput{TableName: aws.String("my-table")}).
+					Return(&dynamodb.DescribeTableOutput{Table: &types.TableDescription{TableArn: aws.String(tableArn)}}, nil).
+					Times(1)
+			},
+			tableArn,
+			nil,
+		},
+		{
+			"DescribeTable error",
+			"my-table",
+			func(mockClient *ddb.MockClient) {
+				mockClient.EXPECT().
+					DescribeTable(gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("oops")).
+					Times(1)
+			},
+			"",
+			errors.New("DescribeTable(my-table): oops"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := ddb.NewMockClient(ctrl)
+			tc.onMock(mockClient)
+			poller, err := NewPoller(PollerOptions{Concurrency: 1}, WithClient(mockClient))
+			if err != nil {
+				t.Fatalf("NewPoller(): %s", err)
+			}
+			got, err := poller.ResolveTableARN(context.Background(), tc.table)
+			assertErr(t, err, tc.wantErr)
+			if got != tc.want {
+				t.Errorf("table ARN:\n\twant=%s\n\tgot=%s", tc.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/go-multierror"
+)
+
+// ErrTargetsRequired is an error that means neither table ARNs nor export ARNs are passed
+var ErrTargetsRequired = errors.New("at least one table ARN or export ARN required")
+
+// Targets is a set of tables and export jobs that the Poller polls at once.
+type Targets struct {
+	// TableArns are the ARNs of the tables whose in-progress export jobs are polled
+	TableArns []string
+
+	// ExportArns are the ARNs of the export jobs to poll
+	ExportArns []string
+}
+
+func (t Targets) validate() error {
+	if len(t.TableArns) == 0 && len(t.ExportArns) == 0 {
+		return ErrTargetsRequired
+	}
+	var errs *multierror.Error
+	for _, tableArn := range t.TableArns {
+		if _, err := ParseTableARN(tableArn); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	for _, exportArn := range t.ExportArns {
+		if _, err := ParseExportARN(exportArn); err != nil {
+			errs = multierror.Append(errs, err)
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// Poll polls the export jobs and in-progress export jobs on the tables at once.
+//
+// All of the export jobs share PollerOptions.Concurrency, PollerOptions.Timeout and PollerOptions.Quorum, and each table and export job is polled only once even if it is given more than once.
+// If listing exports on some tables fails, Poll still polls the export jobs found and returns the errors combined.
+//
+// The results contain every export job polled even if an error occurred.
+func (p *Poller) Poll(ctx context.Context, targets Targets) ([]*ExportResult, error) {
+	if err := targets.validate(); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := p.options.withTimeout(ctx)
+	defer cancel()
+	var errs *multierror.Error
+	seen := map[string]bool{}
+	exportArns := make([]string, 0, len(targets.ExportArns))
+	add := func(arns []string) {
+		for _, exportArn := range arns {
+			if seen[exportArn] {
+				continue
+			}
+			seen[exportArn] = true
+			exportArns = append(exportArns, exportArn)
+		}
+	}
+	add(targets.ExportArns)
+	listed := map[string]bool{}
+	for _, tableArn := range targets.TableArns {
+		if listed[tableArn] {
+			continue
+		}
+		listed[tableArn] = true
+		arns, err := p.exportArnsToWait(ctx, tableArn)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+		add(arns)
+	}
+
+	results, err := p.pollExports(ctx, exportArns, p.options.Quorum, newExportTracker)
+	if err != nil {
+		errs = multierror.Append(errs, err)
+	}
+	return results, errs.ErrorOrNil()
+}
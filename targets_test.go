@@ -0,0 +1,121 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aereal/dynamodb-export-poller/internal/ddb"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/golang/mock/gomock"
+	"github.com/hashicorp/go-multierror"
+)
+
+func TestPoller_Poll(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	tableArn1 := "arn:aws:dynamodb:us-east-1:123456789012:table/table-1"
+	tableArn2 := "arn:aws:dynamodb:us-east-1:123456789012:table/table-2"
+	exportArn1 := tableArn1 + "/export/1"
+	exportArn2 := tableArn2 + "/export/2"
+	exportArn3 := tableArn2 + "/export/3"
+	completed := func(exportArn string) *types.ExportDescription {
+		return &types.ExportDescription{ExportArn: aws.String(exportArn), ExportStatus: types.ExportStatusCompleted}
+	}
+	testCases := []struct {
+		name     string
+		targets  Targets
+		onMock   func(mockClient *ddb.MockClient)
+		wantArns []string
+		wantErr  error
+	}{
+		{
+			"no targets",
+			Targets{},
+			func(mockClient *ddb.MockClient) {},
+			nil,
+			ErrTargetsRequired,
+		},
+		{
+			"invalid ARNs",
+			Targets{TableArns: []string{exportArn1}, ExportArns: []string{tableArn1}},
+			func(mockClient *ddb.MockClient) {},
+			nil,
+			multierror.Append(nil,
+				&InvalidARNError{ARN: exportArn1, Resource: "table", Reason: "resource must be table/TABLE_NAME"},
+				&InvalidARNError{ARN: tableArn1, Resource: "export", Reason: "resource must be table/TABLE_NAME/export/EXPORT_ID"},
+			),
+		},
+		{
+			"tables and exports",
+			Targets{TableArns: []string{tableArn1, tableArn2}, ExportArns: []string{exportArn3}},
+			func(mockClient *ddb.MockClient) {
+				listExportsOf(mockClient, tableArn1, []types.ExportSummary{
+					{ExportArn: aws.String(exportArn1), ExportStatus: types.ExportStatusInProgress},
+				}).Times(1)
+				listExportsOf(mockClient, tableArn2, []types.ExportSummary{
+					{ExportArn: aws.String(exportArn2), ExportStatus: types.ExportStatusInProgress},
+					{ExportArn: aws.String(exportArn3), ExportStatus: types.ExportStatusInProgress},
+				}).Times(1)
+				describeExportOf(mockClient, exportArn1, completed(exportArn1)).Times(1)
+				describeExportOf(mockClient, exportArn2, completed(exportArn2)).Times(1)
+				describeExportOf(mockClient, exportArn3, completed(exportArn3)).Times(1)
+			},
+			[]string{exportArn3, exportArn1, exportArn2},
+			nil,
+		},
+		{
+			"ListExports error on a table",
+			Targets{TableArns: []string{tableArn1, tableArn2}},
+			func(mockClient *ddb.MockClient) {
+				mockClient.EXPECT().
+					ListExports(gomock.Any(), &dynamodb.ListExportsInput{TableArn: aws.String(tableArn1)}).
+					Return(nil, errors.New("oops")).
+					Times(1)
+				listExportsOf(mockClient, tableArn2, []types.ExportSummary{
+					{ExportArn: aws.String(exportArn2), ExportStatus: types.ExportStatusInProgress},
+				}).Times(1)
+				describeExportOf(mockClient, exportArn2, completed(exportArn2)).Times(1)
+			},
+			[]string{exportArn2},
+			multierror.Append(nil, errors.New("ListExports(): oops")),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := ddb.NewMockClient(ctrl)
+			tc.onMock(mockClient)
+			poller, err := NewPoller(PollerOptions{Concurrency: 2, MaxAttempts: 1}, WithClient(mockClient))
+			if err != nil {
+				t.Fatalf("NewPoller(): %s", err)
+			}
+			results, err := poller.Poll(context.Background(), tc.targets)
+			assertErr(t, err, tc.wantErr)
+			gotArns := make([]string, 0, len(results))
+			for _, r := range results {
+				gotArns = append(gotArns, r.ExportArn)
+			}
+			if len(gotArns) != len(tc.wantArns) {
+				t.Fatalf("results:\n\twant=%v\n\tgot=%v", tc.wantArns, gotArns)
+			}
+			for i := range gotArns {
+				if gotArns[i] != tc.wantArns[i] {
+					t.Errorf("results:\n\twant=%v\n\tgot=%v", tc.wantArns, gotArns)
+					break
+				}
+			}
+		})
+	}
+}
+
+func listExportsOf(mockClient *ddb.MockClient, tableArn string, summaries []types.ExportSummary) *gomock.Call {
+	return mockClient.EXPECT().
+		ListExports(gomock.Any(), &dynamodb.ListExportsInput{TableArn: aws.String(tableArn)}).
+		Return(&dynamodb.ListExportsOutput{ExportSummaries: summaries}, nil)
+}
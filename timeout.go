@@ -0,0 +1,96 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// TimeoutError is an error that means export jobs were still in progress when PollerOptions.Timeout or PollerOptions.ExportTimeout was reached.
+//
+// It wraps context.DeadlineExceeded.
+type TimeoutError struct {
+	// Timeout is the time limit reached. It is zero if the deadline of the given context was reached.
+	Timeout time.Duration
+
+	// Exports are the export jobs that were still in progress
+	Exports []TimedOutExport
+}
+
+// TimedOutExport is an export job that was still in progress at the timeout.
+type TimedOutExport struct {
+	// ExportArn is the ARN of the export job
+	ExportArn string
+
+	// Elapsed is the time since the export job started, or since the Poller began waiting if the export job has not been described
+	Elapsed time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	exports := make([]string, len(e.Exports))
+	for i, export := range e.Exports {
+		exports[i] = fmt.Sprintf("%s (in progress for %s)", export.ExportArn, export.Elapsed.Round(time.Second))
+	}
+	timedOut := "timed out"
+	if e.Timeout > 0 {
+		timedOut = fmt.Sprintf("timed out after %s", e.Timeout)
+	}
+	return fmt.Sprintf("%s; %d exports still in progress: %s", timedOut, len(e.Exports), strings.Join(exports, ", "))
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+func newTimeoutError(timeout time.Duration, result *ExportResult) *TimeoutError {
+	elapsed := result.WaitDuration
+	if !result.StartTime.IsZero() {
+		elapsed = time.Since(result.StartTime)
+	}
+	return &TimeoutError{Timeout: timeout, Exports: []TimedOutExport{{ExportArn: result.ExportArn, Elapsed: elapsed}}}
+}
+
+// RequestTimeoutError is an error that means a DynamoDB API request did not respond within PollerOptions.RequestTimeout.
+//
+// DefaultErrorClassifier classifies it as retryable.
+type RequestTimeoutError struct {
+	// Operation is the name of the API such as DescribeExport
+	Operation string
+
+	// Timeout is PollerOptions.RequestTimeout
+	Timeout time.Duration
+
+	// Err is the error the request returned
+	Err error
+}
+
+func (e *RequestTimeoutError) Error() string {
+	return fmt.Sprintf("%s request timed out after %s: %s", e.Operation, e.Timeout, e.Err)
+}
+
+func (e *RequestTimeoutError) Unwrap() error {
+	return e.Err
+}
+
+func (o PollerOptions) withExportTimeout(parent context.Context) (context.Context, func()) {
+	if o.ExportTimeout == 0 {
+		return parent, noop
+	}
+	return context.WithTimeout(parent, o.ExportTimeout)
+}
+
+func (o PollerOptions) withRequestTimeout(parent context.Context) (context.Context, func()) {
+	if o.RequestTimeout == 0 {
+		return parent, noop
+	}
+	return context.WithTimeout(parent, o.RequestTimeout)
+}
+
+// requestTimeoutError wraps err with RequestTimeoutError if only the request context reached its deadline.
+func (o PollerOptions) requestTimeoutError(ctx, reqCtx context.Context, operation string, err error) error {
+	if err == nil || ctx.Err() != nil || reqCtx.Err() != context.DeadlineExceeded {
+		return err
+	}
+	return &RequestTimeoutError{Operation: operation, Timeout: o.RequestTimeout, Err: err}
+}
@@ -0,0 +1,380 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aereal/dynamodb-export-poller/internal/ddb"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/golang/mock/gomock"
+)
+
+func TestTimeoutError_Error(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  *TimeoutError
+		want string
+	}{
+		{
+			"timeout",
+			&TimeoutError{Timeout: 10 * time.Minute, Exports: []TimedOutExport{{ExportArn: "arn1", Elapsed: 12*time.Minute + 300*time.Millisecond}, {ExportArn: "arn2", Elapsed: time.Hour}}},
+			"timed out after 10m0s; 2 exports still in progress: arn1 (in progress for 12m0s), arn2 (in progress for 1h0m0s)",
+		},
+		{
+			"deadline of the context",
+			&TimeoutError{Exports: []TimedOutExport{{ExportArn: "arn1", Elapsed: time.Minute}}},
+			"timed out; 1 exports still in progress: arn1 (in progress for 1m0s)",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := tc.err.Error(); got != tc.want {
+				t.Errorf("message:\n\twant=%s\n\tgot=%s", tc.want, got)
+			}
+			if !errors.Is(tc.err, context.DeadlineExceeded) {
+				t.Error("want to wrap context.DeadlineExceeded")
+			}
+		})
+	}
+}
+
+func TestPoller_timeout(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	tableArn := "arn:aws:dynamodb:us-east-1:123456789012:table/my-table"
+	exportArns := []string{tableArn + "/export/1", tableArn + "/export/2"}
+	startTime := time.Now().Add(-time.Hour)
+	inProgress := func(exportArn string) *types.ExportDescription {
+		return &types.ExportDescription{ExportArn: aws.String(exportArn), ExportStatus: types.ExportStatusInProgress, StartTime: aws.Time(startTime)}
+	}
+	listInProgress := func(mockClient *ddb.MockClient) {
+		listExports(mockClient, []types.ExportSummary{
+			{ExportArn: aws.String(exportArns[0]), ExportStatus: types.ExportStatusInProgress},
+			{ExportArn: aws.String(exportArns[1]), ExportStatus: types.ExportStatusInProgress},
+		}).Times(1)
+	}
+	testCases := []struct {
+		name        string
+		options     PollerOptions
+		onTable     bool
+		onMock      func(mockClient *ddb.MockClient)
+		wantTimeout time.Duration
+		wantArns    []string
+		wantElapsed []time.Duration
+	}{
+		{
+			"PollExport applies timeout",
+			PollerOptions{Concurrency: 1, InitialDelay: time.Millisecond, Timeout: 50 * time.Millisecond},
+			false,
+			func(mockClient *ddb.MockClient) {
+				describeExportOf(mockClient, exportArns[0], inProgress(exportArns[0])).MinTimes(1)
+			},
+			50 * time.Millisecond,
+			[]string{exportArns[0]},
+			[]time.Duration{time.Hour},
+		},
+		{
+			"PollExport applies export timeout",
+			PollerOptions{Concurrency: 1, InitialDelay: time.Millisecond, ExportTimeout: 50 * time.Millisecond},
+			false,
+			func(mockClient *ddb.MockClient) {
+				describeExportOf(mockClient, exportArns[0], inProgress(exportArns[0])).MinTimes(1)
+			},
+			50 * time.Millisecond,
+			[]string{exportArns[0]},
+			[]time.Duration{time.Hour},
+		},
+		{
+			"exports on the table timed out at once",
+			PollerOptions{Concurrency: 2, InitialDelay: time.Millisecond, Timeout: 50 * time.Millisecond},
+			true,
+			func(mockClient *ddb.MockClient) {
+				listInProgress(mockClient)
+				describeExportOf(mockClient, exportArns[0], inProgress(exportArns[0])).MinTimes(1)
+				describeExportOf(mockClient, exportArns[1], inProgress(exportArns[1])).MinTimes(1)
+			},
+			50 * time.Millisecond,
+			exportArns,
+			[]time.Duration{time.Hour, time.Hour},
+		},
+		{
+			"export waiting for the semaphore timed out",
+			PollerOptions{Concurrency: 1, InitialDelay: time.Millisecond, Timeout: 50 * time.Millisecond},
+			true,
+			func(mockClient *ddb.MockClient) {
+				listInProgress(mockClient)
+				describeExportOf(mockClient, exportArns[0], inProgress(exportArns[0])).MinTimes(1)
+				// the semaphore released at the timeout may be acquired before the context is done
+				describeExportOf(mockClient, exportArns[1], inProgress(exportArns[1])).AnyTimes()
+			},
+			50 * time.Millisecond,
+			exportArns,
+			// the export never polled is in progress for the time waited
+			[]time.Duration{time.Hour, 50 * time.Millisecond},
+		},
+		{
+			"only the slow export timed out",
+			PollerOptions{Concurrency: 2, InitialDelay: time.Millisecond, ExportTimeout: 50 * time.Millisecond},
+			true,
+			func(mockClient *ddb.MockClient) {
+				listInProgress(mockClient)
+				describeExportOf(mockClient, exportArns[0], &types.ExportDescription{ExportArn: aws.String(exportArns[0]), ExportStatus: types.ExportStatusCompleted}).Times(1)
+				describeExportOf(mockClient, exportArns[1], inProgress(exportArns[1])).MinTimes(1)
+			},
+			50 * time.Millisecond,
+			exportArns[1:],
+			[]time.Duration{time.Hour},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := ddb.NewMockClient(ctrl)
+			tc.onMock(mockClient)
+			poller, err := NewPoller(tc.options, WithClient(mockClient))
+			if err != nil {
+				t.Fatalf("NewPoller(): %s", err)
+			}
+			if tc.onTable {
+				_, err = poller.PollExportsOnTable(context.Background(), tableArn)
+			} else {
+				_, err = poller.PollExport(context.Background(), exportArns[0])
+			}
+			var timeoutErr *TimeoutError
+			if !errors.As(err, &timeoutErr) {
+				t.Fatalf("want TimeoutError but got %v", err)
+			}
+			if timeoutErr.Timeout != tc.wantTimeout {
+				t.Errorf("timeout: want=%s got=%s", tc.wantTimeout, timeoutErr.Timeout)
+			}
+			gotArns := make([]string, len(timeoutErr.Exports))
+			for i, export := range timeoutErr.Exports {
+				gotArns[i] = export.ExportArn
+				if i < len(tc.wantElapsed) && export.Elapsed < tc.wantElapsed[i] {
+					t.Errorf("elapsed of %s: want at least %s but got %s", export.ExportArn, tc.wantElapsed[i], export.Elapsed)
+				}
+			}
+			if !reflect.DeepEqual(gotArns, tc.wantArns) {
+				t.Errorf("timed out exports:\n\twant=%v\n\tgot=%v", tc.wantArns, gotArns)
+			}
+		})
+	}
+}
+
+func TestPoller_PollExport_requestTimeout(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	exportArn := "arn:aws:dynamodb:us-east-1:123456789012:table/my-table/export/1"
+	mockClient := ddb.NewMockClient(ctrl)
+	seq(
+		mockClient.EXPECT().
+			DescribeExport(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, input *dynamodb.DescribeExportInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeExportOutput, error) {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}).
+			Times(1),
+		describeExport(mockClient, &types.ExportDescription{ExportArn: aws.String(exportArn), ExportStatus: types.ExportStatusCompleted}).Times(1),
+	)
+	poller, err := NewPoller(PollerOptions{Concurrency: 1, MaxAttempts: 2, RequestTimeout: 10 * time.Millisecond}, WithClient(mockClient))
+	if err != nil {
+		t.Fatalf("NewPoller(): %s", err)
+	}
+	result, err := poller.PollExport(context.Background(), exportArn)
+	if err != nil {
+		t.Fatalf("PollExport(): %s", err)
+	}
+	if result.Polls != 2 {
+		t.Errorf("polls: want=2 got=%d", result.Polls)
+	}
+}
+
+func TestPoller_timeoutBeforePolling(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	tableArn := "arn:aws:dynamodb:us-east-1:123456789012:table/my-table"
+	testCases := []struct {
+		name string
+		run  func(ctx context.Context, poller *Poller) error
+	}{
+		{
+			"PollExportsOnTable",
+			func(ctx context.Context, poller *Poller) error {
+				_, err := poller.PollExportsOnTable(ctx, tableArn)
+				return err
+			},
+		},
+		{
+			"Poll",
+			func(ctx context.Context, poller *Poller) error {
+				_, err := poller.Poll(ctx, Targets{TableArns: []string{tableArn}})
+				return err
+			},
+		},
+		{
+			"Watch",
+			func(ctx context.Context, poller *Poller) error {
+				_, err := poller.Watch(ctx, tableArn)
+				return err
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := ddb.NewMockClient(ctrl)
+			listExports(mockClient, nil).MinTimes(1)
+			poller, err := NewPoller(PollerOptions{
+				Concurrency:  1,
+				InitialDelay: time.Millisecond,
+				MaxDelay:     time.Millisecond,
+				Timeout:      100 * time.Millisecond,
+				MinExports:   1,
+				Filter:       ExportFilter{StartedAfter: time.Now()},
+			}, WithClient(mockClient))
+			if err != nil {
+				t.Fatalf("NewPoller(): %s", err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			startedAt := time.Now()
+			err = tc.run(ctx, poller)
+			if elapsed := time.Since(startedAt); elapsed > time.Second {
+				t.Errorf("want to return at the timeout but took %s", elapsed)
+			}
+			var notAppearedErr *ExportsNotAppearedError
+			if !errors.As(err, &notAppearedErr) {
+				t.Errorf("want ExportsNotAppearedError but got %v", err)
+			}
+		})
+	}
+}
+
+func TestPoller_timeoutBeforeWaiting(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	tableArn := "arn:aws:dynamodb:us-east-1:123456789012:table/my-table"
+	req := ExportRequest{TableArn: tableArn, S3Bucket: "my-bucket"}
+	testCases := []struct {
+		name   string
+		onMock func(mockClient *ddb.MockClient, block func(ctx context.Context) error)
+		run    func(ctx context.Context, poller *Poller) error
+	}{
+		{
+			"ExportAndWait",
+			func(mockClient *ddb.MockClient, block func(ctx context.Context) error) {
+				mockClient.EXPECT().
+					ExportTableToPointInTime(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, input *dynamodb.ExportTableToPointInTimeInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ExportTableToPointInTimeOutput, error) {
+						return nil, block(ctx)
+					}).
+					Times(1)
+			},
+			func(ctx context.Context, poller *Poller) error {
+				_, err := poller.ExportAndWait(ctx, req)
+				return err
+			},
+		},
+		{
+			"IncrementalExportAndWait",
+			func(mockClient *ddb.MockClient, block func(ctx context.Context) error) {
+				mockClient.EXPECT().
+					ListExports(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, input *dynamodb.ListExportsInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ListExportsOutput, error) {
+						return nil, block(ctx)
+					}).
+					Times(1)
+			},
+			func(ctx context.Context, poller *Poller) error {
+				_, err := poller.IncrementalExportAndWait(ctx, req)
+				return err
+			},
+		},
+		{
+			"PollExportsOnTables",
+			func(mockClient *ddb.MockClient, block func(ctx context.Context) error) {
+				mockClient.EXPECT().
+					ListTables(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, input *dynamodb.ListTablesInput, optFns ...func(*dynamodb.Options)) (*dynamodb.ListTablesOutput, error) {
+						return nil, block(ctx)
+					}).
+					Times(1)
+			},
+			func(ctx context.Context, poller *Poller) error {
+				_, err := poller.PollExportsOnTables(ctx, TableSelector{NamePattern: "my-*"})
+				return err
+			},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := ddb.NewMockClient(ctrl)
+			tc.onMock(mockClient, func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			})
+			poller, err := NewPoller(PollerOptions{Concurrency: 1, Timeout: 50 * time.Millisecond}, WithClient(mockClient))
+			if err != nil {
+				t.Fatalf("NewPoller(): %s", err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			startedAt := time.Now()
+			err = tc.run(ctx, poller)
+			if elapsed := time.Since(startedAt); elapsed > time.Second {
+				t.Errorf("want to return at the timeout but took %s", elapsed)
+			}
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("want context.DeadlineExceeded but got %v", err)
+			}
+		})
+	}
+}
+
+func TestPoller_ResolveTableARN_requestTimeout(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := ddb.NewMockClient(ctrl)
+	mockClient.EXPECT().
+		DescribeTable(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input *dynamodb.DescribeTableInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DescribeTableOutput, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}).
+		Times(1)
+	poller, err := NewPoller(PollerOptions{Concurrency: 1, RequestTimeout: 10 * time.Millisecond}, WithClient(mockClient))
+	if err != nil {
+		t.Fatalf("NewPoller(): %s", err)
+	}
+	_, err = poller.ResolveTableARN(context.Background(), "my-table")
+	var reqTimeoutErr *RequestTimeoutError
+	if !errors.As(err, &reqTimeoutErr) {
+		t.Fatalf("want RequestTimeoutError but got %v", err)
+	}
+	if reqTimeoutErr.Operation != "DescribeTable" {
+		t.Errorf("operation: want=DescribeTable got=%s", reqTimeoutErr.Operation)
+	}
+}
@@ -0,0 +1,95 @@
+package ddbexportpoller
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+)
+
+// ExportEvent is an event emitted by Watch.
+type ExportEvent struct {
+	// ExportArn is the ARN of the export job the event is about
+	ExportArn string
+
+	// Description is the export job description observed.
+	//
+	// It is nil if the event is emitted without observing new description such as giving up polling.
+	Description *types.ExportDescription
+
+	// Settled means the Poller does not watch the export job any more.
+	Settled bool
+
+	// Result is the result of the export job. It is set only if Settled is true.
+	Result *ExportResult
+
+	// Err is an error occurred while watching the export job. It may be set only if Settled is true.
+	Err error
+}
+
+// Watch watches export jobs and streams their changes.
+//
+// The target is either of a table ARN or an export ARN.
+// If the table ARN is given, Watch watches all in-progress export jobs on the table.
+//
+// Watch emits an event each time it observes a change in the export job description, and an event with Settled field when it stops watching the export job.
+// The returned channel is closed after all export jobs settled.
+//
+// You can configure polling behaviors through PollerOptions.
+func (p *Poller) Watch(ctx context.Context, target string) (<-chan ExportEvent, error) {
+	var exportArns []string
+	pollCtx, cancel := p.options.withTimeout(ctx)
+	if _, err := ParseExportARN(target); err == nil {
+		exportArns = []string{target}
+	} else {
+		if _, err := ParseTableARN(target); err != nil {
+			cancel()
+			return nil, err
+		}
+		exportArns, err = p.exportArnsToWait(pollCtx, target)
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+	}
+
+	ch := make(chan ExportEvent)
+	go func() {
+		defer close(ch)
+		defer cancel()
+		// events are sent until the caller's context is done even if polling is timed out
+		send := func(ev ExportEvent) {
+			select {
+			case ch <- ev:
+			case <-ctx.Done():
+			}
+		}
+		newTracker := func(exportArn string) *exportTracker {
+			tracker := newExportTracker(exportArn)
+			tracker.onObserve = func(prev, curr *types.ExportDescription) {
+				if !descriptionChanged(prev, curr) {
+					return
+				}
+				send(ExportEvent{ExportArn: exportArn, Description: curr})
+			}
+			tracker.onSettle = func(result *ExportResult, err error) {
+				send(ExportEvent{ExportArn: exportArn, Settled: true, Result: result, Err: err})
+			}
+			return tracker
+		}
+		_, _ = p.pollExports(pollCtx, exportArns, QuorumAll, newTracker)
+	}()
+	return ch, nil
+}
+
+func descriptionChanged(prev, curr *types.ExportDescription) bool {
+	if prev == nil || curr == nil {
+		return prev != curr
+	}
+	return prev.ExportStatus != curr.ExportStatus ||
+		aws.ToInt64(prev.ItemCount) != aws.ToInt64(curr.ItemCount) ||
+		aws.ToInt64(prev.BilledSizeBytes) != aws.ToInt64(curr.BilledSizeBytes) ||
+		!aws.ToTime(prev.EndTime).Equal(aws.ToTime(curr.EndTime)) ||
+		aws.ToString(prev.ExportManifest) != aws.ToString(curr.ExportManifest) ||
+		aws.ToString(prev.FailureCode) != aws.ToString(curr.FailureCode)
+}
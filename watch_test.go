@@ -0,0 +1,183 @@
+package ddbexportpoller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/aereal/dynamodb-export-poller/internal/ddb"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/golang/mock/gomock"
+)
+
+func TestPoller_Watch(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	exportArn := "arn:aws:dynamodb:us-east-1:123456789012:table/my-table/export/9012-3456"
+	testCases := []struct {
+		name        string
+		target      string
+		maxAttempts int
+		onMock      func(mockClient *ddb.MockClient)
+		want        []string
+		wantErr     error
+	}{
+		{
+			"invalid target",
+			"",
+			1,
+			func(mockClient *ddb.MockClient) {},
+			nil,
+			ErrTableArnRequired,
+		},
+		{
+			"export ARN",
+			exportArn,
+			4,
+			func(mockClient *ddb.MockClient) {
+				seq(
+					describeExport(mockClient, &types.ExportDescription{ExportStatus: types.ExportStatusInProgress}).Times(1),
+					describeExport(mockClient, &types.ExportDescription{ExportStatus: types.ExportStatusInProgress}).Times(1),
+					describeExport(mockClient, &types.ExportDescription{ExportStatus: types.ExportStatusInProgress, ItemCount: aws.Int64(10)}).Times(1),
+					describeExport(mockClient, &types.ExportDescription{ExportStatus: types.ExportStatusCompleted, ItemCount: aws.Int64(20)}).Times(1),
+				)
+			},
+			[]string{
+				"IN_PROGRESS items=0",
+				"IN_PROGRESS items=10",
+				"COMPLETED items=20",
+				"settled status=COMPLETED err=<nil>",
+			},
+			nil,
+		},
+		{
+			"table ARN",
+			"arn:aws:dynamodb:us-east-1:123456789012:table/my-table",
+			1,
+			func(mockClient *ddb.MockClient) {
+				listExports(
+					mockClient,
+					[]types.ExportSummary{{ExportArn: aws.String(exportArn), ExportStatus: types.ExportStatusInProgress}}).
+					Times(1)
+				describeExport(mockClient, &types.ExportDescription{ExportStatus: types.ExportStatusInProgress}).Times(1)
+			},
+			[]string{
+				"IN_PROGRESS items=0",
+				"settled status=IN_PROGRESS err=export has not been finished",
+			},
+			nil,
+		},
+		{
+			"ListExports error",
+			"arn:aws:dynamodb:us-east-1:123456789012:table/my-table",
+			1,
+			func(mockClient *ddb.MockClient) {
+				mockClient.EXPECT().
+					ListExports(gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("oops")).
+					Times(1)
+			},
+			nil,
+			errors.New("ListExports(): oops"),
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := ddb.NewMockClient(ctrl)
+			tc.onMock(mockClient)
+			poller, err := NewPoller(PollerOptions{Concurrency: 1, MaxAttempts: tc.maxAttempts}, WithClient(mockClient))
+			if err != nil {
+				t.Fatalf("NewPoller(): %s", err)
+			}
+			events, err := poller.Watch(context.Background(), tc.target)
+			assertErr(t, err, tc.wantErr)
+			if err != nil {
+				return
+			}
+			var got []string
+			for ev := range events {
+				if ev.ExportArn != exportArn {
+					t.Errorf("ExportArn:\n\twant=%s\n\tgot=%s", exportArn, ev.ExportArn)
+				}
+				if ev.Settled {
+					got = append(got, fmt.Sprintf("settled status=%s err=%v", ev.Result.Status, ev.Err))
+					continue
+				}
+				got = append(got, fmt.Sprintf("%s items=%d", ev.Description.ExportStatus, aws.ToInt64(ev.Description.ItemCount)))
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("events:\n\twant=%#v\n\tgot=%#v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestPoller_Watch_notStarted(t *testing.T) {
+	clear := setLoggerOutput(t)
+	defer clear()
+
+	tableArn := "arn:aws:dynamodb:us-east-1:123456789012:table/my-table"
+	exportArns := []string{tableArn + "/export/1", tableArn + "/export/2"}
+	testCases := []struct {
+		name    string
+		timeout time.Duration
+		cancel  bool
+	}{
+		{"timeout", 30 * time.Millisecond, false},
+		{"canceled", 0, true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := ddb.NewMockClient(ctrl)
+			listExports(mockClient, []types.ExportSummary{
+				{ExportArn: aws.String(exportArns[0]), ExportStatus: types.ExportStatusInProgress},
+				{ExportArn: aws.String(exportArns[1]), ExportStatus: types.ExportStatusInProgress},
+			}).Times(1)
+			describeExport(mockClient, &types.ExportDescription{ExportStatus: types.ExportStatusInProgress}).MinTimes(1)
+			poller, err := NewPoller(PollerOptions{Concurrency: 1, InitialDelay: time.Millisecond, Timeout: tc.timeout}, WithClient(mockClient))
+			if err != nil {
+				t.Fatalf("NewPoller(): %s", err)
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			events, err := poller.Watch(ctx, tableArn)
+			if err != nil {
+				t.Fatalf("Watch(): %s", err)
+			}
+			if tc.cancel {
+				time.AfterFunc(30*time.Millisecond, cancel)
+			}
+			settled := map[string]error{}
+			for ev := range events {
+				if ev.Settled {
+					settled[ev.ExportArn] = ev.Err
+				}
+			}
+			if tc.cancel {
+				// events after the cancellation are dropped but the channel is closed
+				return
+			}
+			for _, exportArn := range exportArns {
+				err, ok := settled[exportArn]
+				if !ok {
+					t.Errorf("%s: not settled", exportArn)
+					continue
+				}
+				if !errors.Is(err, context.DeadlineExceeded) {
+					t.Errorf("%s: want deadline exceeded but got %v", exportArn, err)
+				}
+			}
+		})
+	}
+}